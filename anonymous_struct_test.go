@@ -0,0 +1,71 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnonymousStructTopLevel(t *testing.T) {
+	type Named struct {
+		A uint32
+		B string
+	}
+
+	anon := struct {
+		A uint32
+		B string
+	}{1, "x"}
+
+	data, err := Marshal(anon)
+	assert.NoError(t, err)
+
+	// Anonymous structs serialize identically to their named equivalents
+	named, err := Marshal(Named{1, "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, named, data)
+
+	var decoded struct {
+		A uint32
+		B string
+	}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, anon, decoded)
+
+	// Data written from an anonymous struct decodes into the named type too
+	var decodedNamed Named
+	assert.NoError(t, Unmarshal(data, &decodedNamed))
+	assert.Equal(t, Named{1, "x"}, decodedNamed)
+}
+
+func TestAnonymousStructFields(t *testing.T) {
+	type Message struct {
+		Header struct {
+			Version uint8
+			Flags   [2]bool
+		}
+		Items []struct {
+			ID   uint16
+			Name string `binary:"4"`
+		}
+		Meta *struct {
+			Note string
+		}
+	}
+
+	original := Message{}
+	original.Header.Version = 3
+	original.Header.Flags = [2]bool{true, false}
+	original.Items = []struct {
+		ID   uint16
+		Name string `binary:"4"`
+	}{{1, "ab"}, {2, "cd"}}
+	original.Meta = &struct{ Note string }{Note: "note"}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Message
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
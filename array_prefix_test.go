@@ -0,0 +1,53 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrayWithoutTagHasNoPrefix(t *testing.T) {
+	// The length of [N]T is known from the type, so only the elements are written
+	data, err := Marshal([5]uint32{1, 2, 3, 4, 5})
+	assert.NoError(t, err)
+	assert.Len(t, data, 5*4)
+	assert.Equal(t, []byte{1, 0, 0, 0, 2, 0, 0, 0}, data[:8])
+
+	var decoded [5]uint32
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, [5]uint32{1, 2, 3, 4, 5}, decoded)
+
+	type Record struct {
+		Pair   [2]uint16
+		Matrix [2][2]int8
+		Names  [2]string
+		Tail   uint8
+	}
+	original := Record{Pair: [2]uint16{1, 2}, Matrix: [2][2]int8{{1, -1}, {2, -2}}, Names: [2]string{"a", ""}, Tail: 9}
+	data, err = Marshal(original)
+	assert.NoError(t, err)
+	// Strings inside the array keep their own length prefix
+	assert.Equal(t, []byte{
+		1, 0, 2, 0,
+		1, 0xFF, 2, 0xFE,
+		1, 0, 0, 0, 'a', 0, 0, 0, 0,
+		9,
+	}, data)
+
+	var decodedRecord Record
+	assert.NoError(t, Unmarshal(data, &decodedRecord))
+	assert.Equal(t, original, decodedRecord)
+}
+
+func TestByteArrayPrefix(t *testing.T) {
+	type Record struct {
+		Plain [3]byte
+		Fixed [3]byte `binary:"3"`
+	}
+
+	// Without a tag, [N]byte is written like []byte with a length prefix; a tag
+	// with the array's length drops the prefix
+	data, err := Marshal(Record{Plain: [3]byte{1, 2, 3}, Fixed: [3]byte{4, 5, 6}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{3, 0, 0, 0, 1, 2, 3, 4, 5, 6}, data)
+}
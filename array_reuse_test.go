@@ -0,0 +1,68 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeArrayShortTagZeroesStaleSlots(t *testing.T) {
+	type Short struct {
+		Values [5]uint32 `binary:"3"`
+	}
+
+	data, err := Marshal(Short{Values: [5]uint32{1, 2, 3, 4, 5}})
+	assert.NoError(t, err)
+	assert.Len(t, data, 12)
+
+	// Slots beyond the tag length are zeroed, not left from the previous value
+	decoded := Short{Values: [5]uint32{9, 9, 9, 9, 9}}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, [5]uint32{1, 2, 3, 0, 0}, decoded.Values)
+
+	type Empty struct {
+		Values [3]uint16 `binary:"0"`
+	}
+	empty := Empty{Values: [3]uint16{7, 7, 7}}
+	assert.NoError(t, Unmarshal([]byte{}, &empty))
+	assert.Equal(t, [3]uint16{}, empty.Values)
+}
+
+func TestDecodeArrayLongTagDiscardsExtras(t *testing.T) {
+	type Long struct {
+		Values [2]uint16 `binary:"4"`
+		After  uint8
+	}
+
+	data := []byte{1, 0, 2, 0, 3, 0, 4, 0, 5}
+	decoded := Long{Values: [2]uint16{9, 9}}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, Long{Values: [2]uint16{1, 2}, After: 5}, decoded)
+}
+
+func TestDecodeArrayShortTagStructElements(t *testing.T) {
+	type Item struct {
+		ID   uint8
+		Note string `binary:"-"`
+	}
+	type Items struct {
+		List [3]Item `binary:"2"`
+	}
+
+	data, err := Marshal(Items{List: [3]Item{{ID: 1}, {ID: 2}, {ID: 3}}})
+	assert.NoError(t, err)
+
+	decoded := Items{List: [3]Item{{ID: 7, Note: "a"}, {ID: 8, Note: "b"}, {ID: 9, Note: "c"}}}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, [3]Item{{ID: 1}, {ID: 2}, {}}, decoded.List)
+}
+
+func TestDecodeByteArrayShortTagZeroesStaleSlots(t *testing.T) {
+	type Short struct {
+		ID [6]byte `binary:"4"`
+	}
+
+	decoded := Short{ID: [6]byte{9, 9, 9, 9, 9, 9}}
+	assert.NoError(t, Unmarshal([]byte{1, 2, 3, 4}, &decoded))
+	assert.Equal(t, [6]byte{1, 2, 3, 4, 0, 0}, decoded.ID)
+}
@@ -0,0 +1,147 @@
+package binary
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// benchOrder is a nested struct with slices of structs and pointers
+type benchOrder struct {
+	ID       uint64
+	Customer benchCustomer
+	Lines    []benchLine
+	Note     *string
+}
+
+type benchCustomer struct {
+	Name    string
+	Email   string
+	Tags    []string
+	Balance float64
+}
+
+type benchLine struct {
+	SKU      string
+	Quantity uint16
+	Price    int64
+}
+
+func exampleOrder() benchOrder {
+	note := "leave at the door"
+	order := benchOrder{
+		ID:       42,
+		Customer: benchCustomer{Name: "Alice", Email: "alice@example.com", Tags: []string{"vip", "eu"}, Balance: 12.5},
+		Note:     &note,
+	}
+	for i := 0; i < 10; i++ {
+		order.Lines = append(order.Lines, benchLine{SKU: fmt.Sprintf("SKU-%03d", i), Quantity: uint16(i + 1), Price: int64(i) * 100})
+	}
+	return order
+}
+
+func exampleMap() map[string]uint32 {
+	m := make(map[string]uint32, 100)
+	for i := 0; i < 100; i++ {
+		m[fmt.Sprintf("key-%d", i)] = uint32(i)
+	}
+	return m
+}
+
+func exampleUint32s() []uint32 {
+	values := make([]uint32, 10000)
+	for i := range values {
+		values[i] = uint32(i)
+	}
+	return values
+}
+
+// allocsPerMarshal returns the average number of allocations of Marshal(v)
+func allocsPerMarshal(v interface{}) float64 {
+	return testing.AllocsPerRun(100, func() {
+		if _, err := Marshal(v); err != nil {
+			panic(err)
+		}
+	})
+}
+
+// allocsPerUnmarshal returns the average number of allocations of decoding data into v
+func allocsPerUnmarshal(data []byte, v interface{}) float64 {
+	return testing.AllocsPerRun(100, func() {
+		if err := Unmarshal(data, v); err != nil {
+			panic(err)
+		}
+	})
+}
+
+func TestTagParsingDoesNotAllocate(t *testing.T) {
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		_, _ = parseTag("omitempty,prefix:2,prefixbe,varint")
+	}))
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		hasTagOption("omitempty,prefix:2,packed", "packed")
+	}))
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		elementTag("")
+	}))
+}
+
+func TestAllocationsPerOperation(t *testing.T) {
+	// Loose bounds that catch regressions such as tags being split on every
+	// call, which used to cost about 80 allocations per Marshal of a Person
+	person := examplePerson()
+	assert.Less(t, allocsPerMarshal(person), 45.0)
+
+	data := MustMarshal(person)
+	var decoded Person
+	assert.Less(t, allocsPerUnmarshal(data, &decoded), 55.0)
+
+	// Bulk slices allocate a fixed number of times regardless of their length
+	values := exampleUint32s()
+	assert.Less(t, allocsPerMarshal(values), 20.0)
+}
+
+func benchmarkMarshal(b *testing.B, v interface{}) {
+	data := MustMarshal(v)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Marshal(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkUnmarshal(b *testing.B, v interface{}, newValue func() interface{}) {
+	data := MustMarshal(v)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Unmarshal(data, newValue()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalPerson(b *testing.B) {
+	benchmarkUnmarshal(b, examplePerson(), func() interface{} { return new(Person) })
+}
+
+func BenchmarkMarshalNested(b *testing.B) {
+	benchmarkMarshal(b, exampleOrder())
+}
+
+func BenchmarkUnmarshalNested(b *testing.B) {
+	benchmarkUnmarshal(b, exampleOrder(), func() interface{} { return new(benchOrder) })
+}
+
+func BenchmarkMarshalMap(b *testing.B) {
+	benchmarkMarshal(b, exampleMap())
+}
+
+func BenchmarkUnmarshalMap(b *testing.B) {
+	benchmarkUnmarshal(b, exampleMap(), func() interface{} { return new(map[string]uint32) })
+}
@@ -0,0 +1,140 @@
+package binary
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+var (
+	bigIntType = reflect.TypeOf(big.Int{})
+	bigRatType = reflect.TypeOf(big.Rat{})
+)
+
+// encodeBigInt serializes a big.Int as a sign byte (0 for zero and positive
+// values, 1 for negative values) followed by the length-prefixed big-endian
+// magnitude. Zero has an empty magnitude.
+func encodeBigInt(x *big.Int, buf *encodeState, tag string) error {
+	if err := encodeSign(x.Sign(), buf); err != nil {
+		return err
+	}
+	return encodeMagnitude(x, buf, tag)
+}
+
+// decodeBigInt deserializes a big.Int written by encodeBigInt
+func decodeBigInt(buf *decodeState, field reflect.Value, tag string) error {
+	negative, err := decodeSign(buf, "big.Int")
+	if err != nil {
+		return err
+	}
+	x, err := decodeMagnitude(buf, tag)
+	if err != nil {
+		return err
+	}
+	if negative {
+		if x.Sign() == 0 {
+			return fmt.Errorf("invalid big.Int: negative zero")
+		}
+		x.Neg(x)
+	}
+	if field.CanAddr() {
+		field.Addr().Interface().(*big.Int).Set(x)
+	} else {
+		field.Set(reflect.ValueOf(x).Elem())
+	}
+	return nil
+}
+
+// encodeBigRat serializes a big.Rat in lowest terms as a sign byte, like
+// big.Int, followed by the length-prefixed big-endian magnitudes of the
+// numerator and of the denominator, which is always positive
+func encodeBigRat(x *big.Rat, buf *encodeState, tag string) error {
+	if err := encodeSign(x.Sign(), buf); err != nil {
+		return err
+	}
+	if err := encodeMagnitude(x.Num(), buf, tag); err != nil {
+		return err
+	}
+	return encodeMagnitude(x.Denom(), buf, tag)
+}
+
+// decodeBigRat deserializes a big.Rat written by encodeBigRat. A fraction that
+// is not in lowest terms is accepted and normalized.
+func decodeBigRat(buf *decodeState, field reflect.Value, tag string) error {
+	negative, err := decodeSign(buf, "big.Rat")
+	if err != nil {
+		return err
+	}
+	num, err := decodeMagnitude(buf, tag)
+	if err != nil {
+		return err
+	}
+	denom, err := decodeMagnitude(buf, tag)
+	if err != nil {
+		return err
+	}
+	if denom.Sign() == 0 {
+		return fmt.Errorf("invalid big.Rat: zero denominator")
+	}
+	if negative {
+		if num.Sign() == 0 {
+			return fmt.Errorf("invalid big.Rat: negative zero")
+		}
+		num.Neg(num)
+	}
+	x := new(big.Rat).SetFrac(num, denom)
+	if field.CanAddr() {
+		field.Addr().Interface().(*big.Rat).Set(x)
+	} else {
+		field.Set(reflect.ValueOf(x).Elem())
+	}
+	return nil
+}
+
+// encodeSign writes the sign byte of a big number: 1 if sign is negative, 0 otherwise
+func encodeSign(sign int, buf *encodeState) error {
+	var b byte
+	if sign < 0 {
+		b = 1
+	}
+	_, err := buf.Write([]byte{b})
+	return err
+}
+
+// decodeSign reads the sign byte written by encodeSign and reports whether it is negative
+func decodeSign(buf *decodeState, typ string) (bool, error) {
+	sign, err := buf.ReadByte()
+	if err != nil {
+		return false, err
+	}
+	if sign > 1 {
+		return false, fmt.Errorf("invalid %s sign byte: %d", typ, sign)
+	}
+	return sign == 1, nil
+}
+
+// encodeMagnitude writes the absolute value of x as a length-prefixed big-endian byte string
+func encodeMagnitude(x *big.Int, buf *encodeState, tag string) error {
+	magnitude := x.Bytes()
+	if err := writeLength(buf, len(magnitude), tag); err != nil {
+		return err
+	}
+	_, err := buf.Write(magnitude)
+	return err
+}
+
+// decodeMagnitude reads a magnitude written by encodeMagnitude
+func decodeMagnitude(buf *decodeState, tag string) (*big.Int, error) {
+	length, err := readLength(buf, tag)
+	if err != nil {
+		return nil, err
+	}
+	if err := buf.checkLength(length); err != nil {
+		return nil, err
+	}
+	magnitude, err := buf.readBytes(length)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(magnitude), nil
+}
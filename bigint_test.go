@@ -0,0 +1,57 @@
+package binary
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBigIntRoundTrip(t *testing.T) {
+	type Account struct {
+		Balance big.Int
+		Limit   *big.Int
+		Key     *big.Int
+	}
+
+	large, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 2048))
+	assert.NoError(t, err)
+	large.SetBit(large, 2047, 1)
+	negative, ok := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	assert.True(t, ok)
+
+	original := Account{Balance: *negative, Limit: new(big.Int), Key: large}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Account
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, 0, negative.Cmp(&decoded.Balance))
+	assert.Equal(t, 0, decoded.Limit.Sign())
+	assert.Equal(t, 0, large.Cmp(decoded.Key))
+	assert.Equal(t, 2048, decoded.Key.BitLen())
+}
+
+func TestBigIntWireFormat(t *testing.T) {
+	// Sign byte, length prefix and big-endian magnitude
+	data, err := Marshal(big.NewInt(-0x1234))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 0, 0, 0, 0x12, 0x34}, data)
+
+	var x big.Int
+	assert.NoError(t, Unmarshal(data, &x))
+	assert.Equal(t, int64(-0x1234), x.Int64())
+
+	// Zero has an empty magnitude
+	data, err = Marshal(new(big.Int))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0}, data)
+	x.SetInt64(99)
+	assert.NoError(t, Unmarshal(data, &x))
+	assert.Equal(t, 0, x.Sign())
+
+	assert.Error(t, Unmarshal([]byte{1, 0, 0, 0, 0}, &x))
+	assert.Error(t, Unmarshal([]byte{2, 0, 0, 0, 0}, &x))
+}
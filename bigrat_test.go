@@ -0,0 +1,74 @@
+package binary
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBigRatRoundTrip(t *testing.T) {
+	large, ok := new(big.Rat).SetString("123456789012345678901234567890/98765432109876543210987654321")
+	assert.True(t, ok)
+
+	for _, x := range []*big.Rat{
+		big.NewRat(1, 3),
+		large,
+		big.NewRat(-7, 2),
+		new(big.Rat),
+		big.NewRat(5, 1),
+	} {
+		data, err := Marshal(x)
+		assert.NoError(t, err)
+
+		decoded := new(big.Rat)
+		assert.NoError(t, Unmarshal(data, decoded))
+		assert.Equal(t, 0, x.Cmp(decoded), "%s decoded as %s", x, decoded)
+	}
+}
+
+func TestBigRatLayout(t *testing.T) {
+	data, err := Marshal(big.NewRat(-2, 6))
+	assert.NoError(t, err)
+	// Sign, then numerator and denominator in lowest terms
+	assert.Equal(t, []byte{1, 1, 0, 0, 0, 1, 1, 0, 0, 0, 3}, data)
+}
+
+func TestBigRatFields(t *testing.T) {
+	type Ledger struct {
+		Rate    big.Rat
+		Share   *big.Rat
+		Missing *big.Rat
+		History []big.Rat `binary:"prefix:1"`
+	}
+
+	original := Ledger{
+		Rate:    *big.NewRat(1, 3),
+		Share:   big.NewRat(-22, 7),
+		History: []big.Rat{*big.NewRat(1, 2), *big.NewRat(3, 4)},
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Ledger
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, 0, original.Rate.Cmp(&decoded.Rate))
+	assert.Equal(t, 0, original.Share.Cmp(decoded.Share))
+	assert.Nil(t, decoded.Missing)
+	assert.Len(t, decoded.History, 2)
+	assert.Equal(t, 0, original.History[1].Cmp(&decoded.History[1]))
+}
+
+func TestBigRatInvalid(t *testing.T) {
+	var x big.Rat
+	// Zero denominator
+	assert.Error(t, Unmarshal([]byte{0, 1, 0, 0, 0, 1, 0, 0, 0, 0}, &x))
+	// Negative zero
+	assert.Error(t, Unmarshal([]byte{1, 0, 0, 0, 0, 1, 0, 0, 0, 1}, &x))
+	// Invalid sign byte
+	assert.Error(t, Unmarshal([]byte{2, 0, 0, 0, 0, 1, 0, 0, 0, 1}, &x))
+
+	// A fraction that is not in lowest terms is normalized
+	assert.NoError(t, Unmarshal([]byte{0, 1, 0, 0, 0, 2, 1, 0, 0, 0, 4}, &x))
+	assert.Equal(t, "1/2", x.String())
+}
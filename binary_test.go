@@ -600,15 +600,34 @@ func TestEncodeDecodeFloats(t *testing.T) {
 func TestParseTag(t *testing.T) {
 	tests := []struct {
 		tag      string
-		expected uint32
+		expected tagInfo
 		hasError bool
 	}{
-		{"", 0, true},
-		{"-", 0, true},
-		{"10", 10, false},
-		{"len:20", 20, false},
-		{"len:abc", 0, true},
-		{"invalid", 0, true},
+		{"", tagInfo{PrefixBytes: 4}, false},
+		{"-", tagInfo{Ignore: true, PrefixBytes: 4}, false},
+		{"10", tagInfo{FixedLen: 10, HasFixedLen: true, PrefixBytes: 4}, false},
+		{"len:20", tagInfo{FixedLen: 20, HasFixedLen: true, PrefixBytes: 4}, false},
+		{"0", tagInfo{FixedLen: 0, HasFixedLen: true, PrefixBytes: 4}, false},
+		{"cstr:8", tagInfo{FixedLen: 8, HasFixedLen: true, CString: true, PrefixBytes: 4}, false},
+		{"raw:16", tagInfo{FixedLen: 16, HasFixedLen: true, Raw: true, PrefixBytes: 4}, false},
+		{"prefix:2,prefixbe", tagInfo{PrefixBytes: 2, HasPrefixBytes: true, PrefixBigEndian: true}, false},
+		{"countvarint", tagInfo{PrefixBytes: 4, CountVarint: true}, false},
+		{"varint", tagInfo{PrefixBytes: 4, Varint: true}, false},
+		{"omitempty,4", tagInfo{FixedLen: 4, HasFixedLen: true, PrefixBytes: 4, OmitEmpty: true}, false},
+		{"scale:2", tagInfo{PrefixBytes: 4, Scale: 2, HasScale: true}, false},
+		{"decimal", tagInfo{PrefixBytes: 4, Decimal: true}, false},
+		{"f32", tagInfo{PrefixBytes: 4, Float32: true}, false},
+		{"packed,prefix:2", tagInfo{PrefixBytes: 2, HasPrefixBytes: true, Packed: true}, false},
+		{"utf16,prefix:2", tagInfo{PrefixBytes: 2, HasPrefixBytes: true, UTF16: true}, false},
+		{"count:4,elem:16", tagInfo{FixedLen: 4, HasFixedLen: true, PrefixBytes: 4, ElemLen: 16, HasElemLen: true}, false},
+		{"runes,countvarint", tagInfo{PrefixBytes: 4, CountVarint: true, Runes: true}, false},
+		{"len:abc", tagInfo{}, true},
+		{"invalid", tagInfo{}, true},
+		{"prefix:3", tagInfo{}, true},
+		{"scale:19", tagInfo{}, true},
+		{"countvarint,prefix:2", tagInfo{}, true},
+		{"packed,8", tagInfo{}, true},
+		{"utf16,16", tagInfo{}, true},
 	}
 
 	for _, test := range tests {
@@ -617,7 +636,7 @@ func TestParseTag(t *testing.T) {
 			assert.Error(t, err, "Expected error for tag: %s", test.tag)
 		} else {
 			assert.NoError(t, err, "Unexpected error for tag: %s", test.tag)
-			assert.Equal(t, test.expected, result, "Expected %d for tag: %s", test.expected, test.tag)
+			assert.Equal(t, test.expected, result, "Unexpected options for tag: %s", test.tag)
 		}
 	}
 }
@@ -780,6 +799,11 @@ func TestRemainingDataHandling(t *testing.T) {
 	var result TestStruct
 	err := Unmarshal(data, &result)
 	assert.Error(t, err, "Expected error due to remaining data")
+	assert.ErrorIs(t, err, ErrTrailingData)
+	var trailing *TrailingDataError
+	if assert.ErrorAs(t, err, &trailing) {
+		assert.Equal(t, len(extraData), trailing.Remaining)
+	}
 	assert.Contains(t, err.Error(), "bytes of data remaining", "Error should mention remaining data")
 	t.Logf("Decode error (expected): %v", err)
 
@@ -974,6 +998,37 @@ func TestBoolArray(t *testing.T) {
 	assert.Equal(t, original.Flags, decoded.Flags)
 }
 
+// TestBoolStructRoundTrip tests a struct mixing bool fields, bool slices and bool arrays
+func TestBoolStructRoundTrip(t *testing.T) {
+	type Flags struct {
+		Enabled  bool
+		Visible  bool
+		Archived bool
+		History  []bool
+		Mask     [4]bool
+		Padded   [3]bool `binary:"5"`
+	}
+
+	original := Flags{
+		Enabled:  true,
+		Visible:  false,
+		Archived: true,
+		History:  []bool{false, true, true},
+		Mask:     [4]bool{true, false, false, true},
+		Padded:   [3]bool{true, true, false},
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// Each bool is a single byte: 3 fields + (4 + 3) slice + 4 array + 5 padded array
+	assert.Len(t, data, 19)
+
+	var decoded Flags
+	err = Unmarshal(data, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
+
 // Hash 哈希值类型
 type Hash [32]byte
 
@@ -0,0 +1,125 @@
+package binary
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// bitsTag packs consecutive bool fields of a struct into bytes
+const bitsTag = "bits"
+
+// bitWriter packs the bool fields of a run of "bits" fields, 8 per byte, least significant bit first
+type bitWriter struct {
+	cur byte
+	n   int // number of bits set in cur
+}
+
+// writeBit adds a bool to the current byte, writing the byte once it is full
+func (b *bitWriter) writeBit(buf *encodeState, field reflect.Value) error {
+	if field.Kind() != reflect.Bool {
+		return fmt.Errorf("%w: bits tag requires a bool field, got %s", ErrInvalidTag, field.Kind())
+	}
+	if field.Bool() {
+		b.cur |= 1 << b.n
+	}
+	b.n++
+	if b.n == 8 {
+		return b.flush(buf)
+	}
+	return nil
+}
+
+// flush writes a partially filled byte, ending the current run of bits
+func (b *bitWriter) flush(buf *encodeState) error {
+	if b.n == 0 {
+		return nil
+	}
+	_, err := buf.Write([]byte{b.cur})
+	b.cur, b.n = 0, 0
+	return err
+}
+
+// bitReader unpacks the bool fields written by bitWriter
+type bitReader struct {
+	cur byte
+	n   int // number of bits left in cur
+}
+
+// readBit reads the next bool of the current run, reading a new byte when needed
+func (b *bitReader) readBit(buf *decodeState, field reflect.Value) error {
+	if field.Kind() != reflect.Bool {
+		return fmt.Errorf("%w: bits tag requires a bool field, got %s", ErrInvalidTag, field.Kind())
+	}
+	if b.n == 0 {
+		cur, err := buf.ReadByte()
+		if err != nil {
+			return err
+		}
+		b.cur, b.n = cur, 8
+	}
+	field.SetBool(b.cur&1 == 1)
+	b.cur >>= 1
+	b.n--
+	return nil
+}
+
+// reset ends the current run of bits; the unused bits of its last byte are ignored
+func (b *bitReader) reset() {
+	b.cur, b.n = 0, 0
+}
+
+// packedTag packs the elements of a []bool field into bytes
+const packedTag = "packed"
+
+// encodePackedBools writes a []bool as its element count followed by
+// ceil(n/8) bytes, 8 elements per byte, least significant bit first
+func encodePackedBools(slice reflect.Value, buf *encodeState, tag string) error {
+	if slice.Type().Elem().Kind() != reflect.Bool {
+		return fmt.Errorf("%w: packed tag requires a []bool field, got %s", ErrInvalidTag, slice.Type())
+	}
+	length := slice.Len()
+	if err := writeLength(buf, length, tag); err != nil {
+		return err
+	}
+	data := make([]byte, (length+7)/8)
+	for i := 0; i < length; i++ {
+		if slice.Index(i).Bool() {
+			data[i/8] |= 1 << (i % 8)
+		}
+	}
+	_, err := buf.Write(data)
+	return err
+}
+
+// decodePackedBools reads a []bool written by encodePackedBools. The unused
+// bits of the last byte are ignored.
+func decodePackedBools(buf *decodeState, field reflect.Value, tag string) error {
+	if field.Type().Elem().Kind() != reflect.Bool {
+		return fmt.Errorf("%w: packed tag requires a []bool field, got %s", ErrInvalidTag, field.Type())
+	}
+	length, err := readLength(buf, tag)
+	if err != nil {
+		return err
+	}
+	if err := buf.checkCount(length); err != nil {
+		return err
+	}
+	size := length/8 + min(length%8, 1) // ceil(length/8) without overflowing
+	if err := buf.checkLength(size); err != nil {
+		return err
+	}
+	data := make([]byte, size)
+	if err := buf.readFull(data); err != nil {
+		return err
+	}
+
+	slice, ok := reuseSlice(field, length)
+	if !ok {
+		slice = reflect.MakeSlice(field.Type(), length, length)
+	}
+	for i := 0; i < length; i++ {
+		slice.Index(i).SetBool(data[i/8]&(1<<(i%8)) != 0)
+	}
+	field.Set(slice)
+	return nil
+}
@@ -0,0 +1,68 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type featureFlags struct {
+	A bool `binary:"bits"`
+	B bool `binary:"bits"`
+	C bool `binary:"bits"`
+	D bool `binary:"bits"`
+	E bool `binary:"bits"`
+	F bool `binary:"bits"`
+	G bool `binary:"bits"`
+	H bool `binary:"bits"`
+	I bool `binary:"bits"`
+	J bool `binary:"bits"`
+}
+
+func TestBitsPacking(t *testing.T) {
+	flags := featureFlags{A: true, C: true, H: true, J: true}
+
+	data, err := Marshal(flags)
+	assert.NoError(t, err)
+	// Ten flags fit in two bytes, least significant bit first
+	assert.Equal(t, []byte{0b10000101, 0b00000010}, data)
+
+	var decoded featureFlags
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, flags, decoded)
+}
+
+func TestBitsRunsEndAtOtherFields(t *testing.T) {
+	type Mixed struct {
+		Enabled bool `binary:"bits"`
+		Visible bool `binary:"bits"`
+		Count   uint16
+		Locked  bool `binary:"bits"`
+		Plain   bool
+	}
+
+	original := Mixed{Visible: true, Count: 3, Locked: true, Plain: true}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0b10, 3, 0, 0b1, 1}, data)
+
+	var decoded Mixed
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+
+	// Packed fields report the byte that holds them
+	layout, err := Layout(original)
+	assert.NoError(t, err)
+	assert.Equal(t, FieldLayout{Name: "Visible", Type: "bool", Offset: 0, Length: 1}, layout[1])
+	assert.Equal(t, FieldLayout{Name: "Locked", Type: "bool", Offset: 3, Length: 1}, layout[3])
+}
+
+func TestBitsRequiresBool(t *testing.T) {
+	type Invalid struct {
+		Value uint8 `binary:"bits"`
+	}
+	_, err := Marshal(Invalid{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bits tag requires a bool field")
+	assert.Error(t, Unmarshal([]byte{1}, &Invalid{}))
+}
@@ -0,0 +1,139 @@
+package binary
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// isBulkSlice reports whether the elements of a slice can be written and read
+// in a single binary.Write/binary.Read call instead of one reflective call per
+// element. This holds for predeclared fixed-size numeric and bool types whose
+// encoding is not changed by the element tag; int, uint and uintptr are
+// excluded because their in-memory size differs from their 8-byte encoding on
+// some platforms. Named types are excluded as well, so that their encoding
+// stays with the general path.
+func isBulkSlice(typ reflect.Type, elemTag string) bool {
+	return isBulkElem(typ.Elem(), elemTag)
+}
+
+// isBulkArray reports whether an array, possibly multi-dimensional like
+// [3][3]float64, can be written and read in a single call. Its innermost
+// elements must be accepted by isBulkSlice, except bytes: an inner [N]byte is
+// written with a length prefix, so [M][N]byte takes the general path.
+func isBulkArray(typ reflect.Type, elemTag string) bool {
+	elem := typ.Elem()
+	for elem.Kind() == reflect.Array && elem.PkgPath() == "" {
+		elem = elem.Elem()
+	}
+	return elem.Kind() != reflect.Uint8 && isBulkElem(elem, elemTag)
+}
+
+// isBulkElem reports whether elements of type elem have a fixed-size encoding
+// identical to their binary.Write encoding under the given element tag
+func isBulkElem(elem reflect.Type, elemTag string) bool {
+	if elem.PkgPath() != "" {
+		return false
+	}
+	switch elem.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+	default:
+		return false
+	}
+	if elemTag == "" {
+		return true
+	}
+	info, err := parseTag(elemTag)
+	return err == nil && !info.Varint && !info.Decimal && !info.Float32
+}
+
+// encodeBulkSlice writes all elements of a slice accepted by isBulkSlice at once.
+// The output is identical to encoding the elements one by one.
+func encodeBulkSlice(slice reflect.Value, buf *encodeState) error {
+	if slice.Len() == 0 {
+		return nil
+	}
+	if buf.ctx == nil {
+		return binary.Write(buf, buf.byteOrder(), slice.Interface())
+	}
+	// With a context, write in chunks so that a cancellation is noticed
+	for start := 0; start < slice.Len(); start += cancelCheckInterval {
+		if err := buf.ctx.Err(); err != nil {
+			return err
+		}
+		end := min(start+cancelCheckInterval, slice.Len())
+		if err := binary.Write(buf, buf.byteOrder(), slice.Slice(start, end).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeBulkSlice reads length elements of a slice accepted by isBulkSlice at once
+// and stores them in field, reusing its backing array if it has room
+func decodeBulkSlice(buf *decodeState, field reflect.Value, length int) error {
+	sliceType := field.Type()
+	size := int(sliceType.Elem().Size())
+	if remaining, ok := buf.remaining(); ok && length > remaining/size {
+		return fmt.Errorf("%d elements of %d bytes exceed remaining data of %d bytes: %w", length, size, remaining, errUnexpectedEOF)
+	}
+
+	var slice reflect.Value
+	if field.Cap() > 0 && field.Cap() >= length {
+		slice = field.Slice(0, length)
+	} else {
+		// On a stream the length is not checked against the input, so the slice
+		// starts small and doubles as the elements are read
+		initialLen := buf.initialLen(length, sliceType.Elem())
+		slice = reflect.MakeSlice(sliceType, initialLen, initialLen)
+	}
+	if err := readBulk(buf, slice); err != nil {
+		return err
+	}
+	for slice.Len() < length {
+		read := slice.Len()
+		next := min(max(2*read, 1), length)
+		grown := reflect.MakeSlice(sliceType, next, next)
+		reflect.Copy(grown, slice)
+		slice = grown
+		if err := readBulk(buf, slice.Slice(read, slice.Len())); err != nil {
+			return err
+		}
+	}
+	field.Set(slice)
+	return nil
+}
+
+// readBulk reads all elements of a slice accepted by isBulkSlice
+func readBulk(buf *decodeState, slice reflect.Value) error {
+	if buf.ctx == nil && slice.Len() > 0 {
+		return binary.Read(buf, buf.byteOrder(), slice.Interface())
+	}
+	// With a context, read in chunks so that a cancellation is noticed
+	for start := 0; buf.ctx != nil && start < slice.Len(); start += cancelCheckInterval {
+		if err := buf.ctx.Err(); err != nil {
+			return err
+		}
+		end := min(start+cancelCheckInterval, slice.Len())
+		if err := binary.Read(buf, buf.byteOrder(), slice.Slice(start, end).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeBulkArray reads all elements of an array accepted by isBulkArray at once,
+// in row-major order for multi-dimensional arrays
+func decodeBulkArray(buf *decodeState, field reflect.Value) error {
+	if field.CanAddr() {
+		return binary.Read(buf, buf.byteOrder(), field.Addr().Interface())
+	}
+	array := reflect.New(field.Type())
+	if err := binary.Read(buf, buf.byteOrder(), array.Interface()); err != nil {
+		return err
+	}
+	field.Set(array.Elem())
+	return nil
+}
@@ -0,0 +1,137 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// encodeElementwise writes a slice with a uint32 count followed by each element,
+// the format produced by the general per-element path
+func encodeElementwise(t *testing.T, slice interface{}) []byte {
+	var buf bytes.Buffer
+	v := reflect.ValueOf(slice)
+	assert.NoError(t, binary.Write(&buf, binary.LittleEndian, uint32(v.Len())))
+	for i := 0; i < v.Len(); i++ {
+		assert.NoError(t, binary.Write(&buf, binary.LittleEndian, v.Index(i).Interface()))
+	}
+	return buf.Bytes()
+}
+
+func TestBulkSliceMatchesElementwise(t *testing.T) {
+	values := []interface{}{
+		[]bool{true, false, true},
+		[]int8{-1, 2},
+		[]int16{-300, 300},
+		[]int32{math.MinInt32, 0, math.MaxInt32},
+		[]int64{math.MinInt64, 1},
+		[]uint16{1, 0xFFFF},
+		[]uint32{1, 2, 0xDEADBEEF},
+		[]uint64{math.MaxUint64},
+		[]float32{0.1, float32(math.Inf(-1))},
+		[]float64{0.1, -2.5, math.MaxFloat64},
+		[]complex64{complex(1, -2)},
+		[]complex128{complex(0.5, 3)},
+		[]uint32{},
+	}
+
+	for _, v := range values {
+		data, err := Marshal(v)
+		assert.NoError(t, err)
+		assert.Equal(t, encodeElementwise(t, v), data, "%T", v)
+
+		decoded := reflect.New(reflect.TypeOf(v))
+		assert.NoError(t, Unmarshal(data, decoded.Interface()))
+		assert.Equal(t, v, decoded.Elem().Interface(), "%T", v)
+	}
+}
+
+func TestBulkSliceReuseAndStream(t *testing.T) {
+	data, err := Marshal([]uint32{7, 8})
+	assert.NoError(t, err)
+
+	decoded := make([]uint32, 5, 10)
+	backing := &decoded[:1][0]
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, []uint32{7, 8}, decoded)
+	assert.Same(t, backing, &decoded[0])
+
+	var streamed []uint32
+	assert.NoError(t, NewDecoder(bytes.NewReader(data)).Decode(&streamed))
+	assert.Equal(t, []uint32{7, 8}, streamed)
+}
+
+func TestBulkSliceTruncated(t *testing.T) {
+	data, err := Marshal([]uint64{1, 2, 3})
+	assert.NoError(t, err)
+
+	var decoded []uint64
+	assert.ErrorIs(t, Unmarshal(data[:len(data)-1], &decoded), io.ErrUnexpectedEOF)
+
+	// A count far larger than the input is rejected before allocating
+	assert.ErrorIs(t, Unmarshal([]byte{0xFF, 0xFF, 0xFF, 0x7F, 1}, &decoded), io.ErrUnexpectedEOF)
+
+	var streamed []uint64
+	assert.ErrorIs(t, NewDecoder(bytes.NewReader(data[:len(data)-1])).Decode(&streamed), io.ErrUnexpectedEOF)
+}
+
+func TestBulkSliceSkippedForTagsAndNamedTypes(t *testing.T) {
+	type Level uint16
+	type Record struct {
+		Varints []uint32  `binary:"varint"`
+		Prices  []float64 `binary:"decimal"`
+		Levels  []Level
+		Ints    []int
+	}
+
+	original := Record{
+		Varints: []uint32{1, 300},
+		Prices:  []float64{0.1},
+		Levels:  []Level{1, 2},
+		Ints:    []int{-1},
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// 4+1+2 for the varints, 4+4+3 for the decimal, 4+2*2 for the levels, 4+8 for the ints
+	assert.Len(t, data, 7+11+8+12)
+
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func BenchmarkMarshalUint32Slice(b *testing.B) {
+	values := make([]uint32, 1<<20)
+	for i := range values {
+		values[i] = uint32(i)
+	}
+	b.SetBytes(int64(len(values) * 4))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Marshal(values); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalUint32Slice(b *testing.B) {
+	data, err := Marshal(make([]uint32, 1<<20))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var decoded []uint32
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Unmarshal(data, &decoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteArrayTagLongerThanArray(t *testing.T) {
+	type Record struct {
+		Data [5]byte `binary:"10"`
+	}
+
+	// Encoding pads the array with zeros up to the tag length
+	original := Record{Data: [5]byte{1, 2, 3, 4, 5}}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 0, 0, 0, 0, 0}, data)
+
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+
+	// Bytes beyond the array's size that are not padding would be lost, so decoding fails
+	data[7] = 0xAA
+	err = Unmarshal(data, &decoded)
+	assert.ErrorContains(t, err, "offset 7")
+	assert.ErrorContains(t, err, "[5]uint8")
+	var fieldErr *FieldError
+	if assert.ErrorAs(t, err, &fieldErr) {
+		assert.Equal(t, "Data", fieldErr.Path)
+	}
+}
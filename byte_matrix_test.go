@@ -0,0 +1,63 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteSliceOfSlices(t *testing.T) {
+	original := [][]byte{{1, 2}, {3}, {}}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// Count, then each inner slice with its own length prefix
+	assert.Equal(t, []byte{
+		3, 0, 0, 0,
+		2, 0, 0, 0, 1, 2,
+		1, 0, 0, 0, 3,
+		0, 0, 0, 0,
+	}, data)
+
+	var decoded [][]byte
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestByteSliceOfSlicesWithNil(t *testing.T) {
+	original := [][]byte{{1, 2, 3}, nil, {4}}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded [][]byte
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Len(t, decoded, 3)
+	assert.Equal(t, []byte{1, 2, 3}, decoded[0])
+	// A nil inner slice is encoded like an empty one and decodes as empty
+	assert.Empty(t, decoded[1])
+	assert.Equal(t, []byte{4}, decoded[2])
+}
+
+func TestByteMatrixInStruct(t *testing.T) {
+	type Frames struct {
+		Rows   [][]byte
+		Fixed  [][]byte `binary:"2"`
+		Blocks [][2]byte
+		Nested [][][]byte
+	}
+
+	original := Frames{
+		Rows:   [][]byte{{0xff}, {}, {0x10, 0x20}},
+		Fixed:  [][]byte{{7, 8, 9}, {}},
+		Blocks: [][2]byte{{1, 2}, {3, 4}},
+		Nested: [][][]byte{{{1}, {}}, {}, {{2, 3}}},
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Frames
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
@@ -0,0 +1,39 @@
+package binary
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// checksumSize is the length of the CRC32 trailer appended by MarshalWithChecksum
+const checksumSize = 4
+
+// ErrChecksumMismatch is returned by UnmarshalWithChecksum when the trailer does not match the data
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// MarshalWithChecksum marshals v and appends a 4-byte little-endian CRC32 (IEEE)
+// of the encoded value, so corruption can be detected when the data is read back
+func MarshalWithChecksum(v interface{}) ([]byte, error) {
+	data, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data)), nil
+}
+
+// UnmarshalWithChecksum verifies the CRC32 trailer written by MarshalWithChecksum
+// and then unmarshals the data before it into v. If the checksum does not match,
+// v is left untouched and an error wrapping ErrChecksumMismatch is returned.
+func UnmarshalWithChecksum(data []byte, v interface{}) error {
+	if len(data) < checksumSize {
+		return fmt.Errorf("data of %d bytes is too short to contain a checksum: %w", len(data), errUnexpectedEOF)
+	}
+	payload := data[:len(data)-checksumSize]
+	want := binary.LittleEndian.Uint32(data[len(payload):])
+	if got := crc32.ChecksumIEEE(payload); got != want {
+		return fmt.Errorf("%w: computed %08x, trailer %08x", ErrChecksumMismatch, got, want)
+	}
+	return Unmarshal(payload, v)
+}
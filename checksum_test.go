@@ -0,0 +1,53 @@
+package binary
+
+import (
+	"errors"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksumRoundTrip(t *testing.T) {
+	person := examplePerson()
+
+	data, err := MarshalWithChecksum(person)
+	assert.NoError(t, err)
+
+	// The trailer covers exactly the regular encoding
+	plain, err := Marshal(person)
+	assert.NoError(t, err)
+	assert.Equal(t, plain, data[:len(plain)])
+	assert.Len(t, data, len(plain)+4)
+	sum := crc32.ChecksumIEEE(plain)
+	assert.Equal(t, []byte{byte(sum), byte(sum >> 8), byte(sum >> 16), byte(sum >> 24)}, data[len(plain):])
+
+	var decoded Person
+	assert.NoError(t, UnmarshalWithChecksum(data, &decoded))
+	assert.Equal(t, person.Name, decoded.Name)
+	assert.Equal(t, person.Values, decoded.Values)
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	data, err := MarshalWithChecksum(examplePerson())
+	assert.NoError(t, err)
+
+	// Flip a single bit in the payload
+	corrupted := append([]byte(nil), data...)
+	corrupted[10] ^= 0x01
+
+	var decoded Person
+	err = UnmarshalWithChecksum(corrupted, &decoded)
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+	assert.Equal(t, Person{}, decoded)
+
+	// Corruption of the trailer itself is detected too
+	corrupted = append([]byte(nil), data...)
+	corrupted[len(corrupted)-1] ^= 0x80
+	assert.True(t, errors.Is(UnmarshalWithChecksum(corrupted, &decoded), ErrChecksumMismatch))
+
+	// Data too short to hold a trailer is not a checksum mismatch
+	err = UnmarshalWithChecksum([]byte{1, 2}, &decoded)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrChecksumMismatch))
+}
@@ -0,0 +1,40 @@
+package binary
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoderClearBeforeDecode(t *testing.T) {
+	type Record struct {
+		ID    uint32
+		Cache []string `binary:"-"`
+		seen  bool
+	}
+
+	var stream bytes.Buffer
+	for _, id := range []uint32{1, 2} {
+		data, err := Marshal(struct{ ID uint32 }{id})
+		assert.NoError(t, err)
+		stream.Write(data)
+	}
+
+	// Without the option, fields not written by the record keep stale values
+	dec := NewDecoder(bytes.NewReader(stream.Bytes()))
+	reused := Record{Cache: []string{"stale"}, seen: true}
+	assert.NoError(t, dec.Decode(&reused))
+	assert.Equal(t, uint32(1), reused.ID)
+	assert.Equal(t, []string{"stale"}, reused.Cache)
+	assert.True(t, reused.seen)
+
+	// With the option, the destination is zeroed before every record
+	dec = NewDecoder(bytes.NewReader(stream.Bytes()))
+	dec.ClearBeforeDecode(true)
+	for _, id := range []uint32{1, 2} {
+		reused = Record{Cache: []string{"stale"}, seen: true}
+		assert.NoError(t, dec.Decode(&reused))
+		assert.Equal(t, Record{ID: id}, reused)
+	}
+}
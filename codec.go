@@ -0,0 +1,229 @@
+package binary
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Codec holds encoding options and provides the marshaling functions of the
+// package with those options applied. The zero value uses the defaults of the
+// package-level functions, which are implemented on top of it. A Codec is an
+// immutable value: the With methods return a modified copy, so a configured
+// codec can be shared by multiple goroutines.
+//
+// Data must be decoded with a codec configured like the one that encoded it.
+type Codec struct {
+	options  codecOptions
+	maxAlloc int
+	ctx      context.Context // set by MarshalContext and UnmarshalContext
+}
+
+// codecOptions holds the Codec settings that affect the encoding, shared by
+// the encoder and decoder state
+type codecOptions struct {
+	order       binary.ByteOrder // byte order of numbers and length prefixes, nil means little-endian
+	prefixBytes int              // width of length prefixes without "prefix:N", 0 means 4 bytes
+	preserveNil bool             // slices and maps start with a presence byte so nil round-trips
+}
+
+// byteOrder returns the byte order of numbers and length prefixes
+func (o codecOptions) byteOrder() binary.ByteOrder {
+	if o.order == nil {
+		return binary.LittleEndian
+	}
+	return o.order
+}
+
+// validate reports options that cannot be used for encoding or decoding
+func (o codecOptions) validate() error {
+	switch o.prefixBytes {
+	case 0, 1, 2, 4, 8:
+		return nil
+	}
+	return fmt.Errorf("%w: invalid length prefix width %d", ErrInvalidTag, o.prefixBytes)
+}
+
+// defaultCodec is used by the package-level functions
+var defaultCodec Codec
+
+// NewCodec returns a Codec with the default options
+func NewCodec() Codec {
+	return Codec{}
+}
+
+// WithByteOrder returns a copy of the codec that writes integers, floats,
+// complex numbers and length prefixes in the given byte order instead of
+// little-endian. This includes the fields of time.Time, the type codes of
+// interfaces and the length written before BinaryMarshaler data, but not the
+// headers and trailers of functions such as MarshalWithChecksum, which only
+// use the default codec. The "prefixbe" tag still selects big-endian prefixes.
+func (c Codec) WithByteOrder(order binary.ByteOrder) Codec {
+	c.options.order = order
+	return c
+}
+
+// WithMaxAlloc returns a copy of the codec that limits the length a single
+// length prefix may declare when decoding, like Decoder.SetMaxAllocSize.
+// A value of 0, the default, disables the limit.
+func (c Codec) WithMaxAlloc(n int) Codec {
+	c.maxAlloc = n
+	return c
+}
+
+// WithPrefixBytes returns a copy of the codec that writes length prefixes with
+// a width of 1, 2, 4 or 8 bytes instead of 4, unless a field selects its own
+// width with "prefix:N". The length written before BinaryMarshaler data stays
+// 4 bytes wide. For any other width, encoding and decoding with the codec fail
+// with an error wrapping ErrInvalidTag.
+func (c Codec) WithPrefixBytes(n int) Codec {
+	c.options.prefixBytes = n
+	return c
+}
+
+// WithPreserveNil returns a copy of the codec that keeps nil and empty slices and
+// maps apart. When enabled, every slice and map, except net.IP and Raw values,
+// starts with a presence byte: 0 for nil, after which nothing follows, or 1
+// followed by the usual encoding. A nil value then decodes as nil and an empty
+// one as a non-nil value of length zero, instead of both decoding as empty.
+func (c Codec) WithPreserveNil(enable bool) Codec {
+	c.options.preserveNil = enable
+	return c
+}
+
+// Marshal serializes a value like the package-level Marshal, using the codec's options
+func (c Codec) Marshal(v interface{}) ([]byte, error) {
+	if err := c.options.validate(); err != nil {
+		return nil, err
+	}
+
+	// Check if the value implements BinaryMarshaler
+	if marshaler, ok := asMarshaler(v); ok {
+		return marshaler.MarshalBinary()
+	}
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+	buf.Reset()
+	if err := encodeValue(v, &encodeState{w: buf, codecOptions: c.options, ctx: c.ctx}); err != nil {
+		return nil, err
+	}
+	if buf.Len() == 0 {
+		return nil, nil
+	}
+	// The result must not alias the buffer, which is reused by later calls
+	data := make([]byte, buf.Len())
+	copy(data, buf.Bytes())
+	return data, nil
+}
+
+// bufferPool holds the buffers that Marshal encodes into, so that a buffer
+// grown by one call saves the next calls from growing their own
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// maxPooledBufferSize is the capacity above which a buffer is not returned to
+// the pool, so that one very large value does not keep its memory alive
+const maxPooledBufferSize = 64 << 10
+
+// putBuffer returns a buffer obtained from bufferPool
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// AppendTo appends the encoding of v to dst and returns the extended slice,
+// like MarshalAppend. On error, dst is returned with its original length.
+func (c Codec) AppendTo(dst []byte, v interface{}) ([]byte, error) {
+	if err := c.options.validate(); err != nil {
+		return dst, err
+	}
+	buf := bytes.NewBuffer(dst)
+	if err := encodeValue(v, &encodeState{w: buf, codecOptions: c.options}); err != nil {
+		return dst, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Size returns the number of bytes that the codec's Marshal would produce for v
+func (c Codec) Size(v interface{}) (int, error) {
+	if err := c.options.validate(); err != nil {
+		return 0, err
+	}
+	buf := &encodeState{w: io.Discard, codecOptions: c.options}
+	if err := encodeValue(v, buf); err != nil {
+		return 0, err
+	}
+	return int(buf.n), nil
+}
+
+// Unmarshal deserializes data into v like the package-level Unmarshal, using
+// the codec's options. It fails if bytes remain after the value, unless v
+// implements ForwardCompatible.
+func (c Codec) Unmarshal(data []byte, v interface{}) error {
+	remaining, err := c.UnmarshalPartial(data, v)
+	if err != nil {
+		return err
+	}
+	if _, ok := v.(ForwardCompatible); ok {
+		// Newer versions of the type may have appended fields
+		return nil
+	}
+	if remaining > 0 {
+		return &TrailingDataError{Remaining: remaining}
+	}
+	return nil
+}
+
+// UnmarshalPartial deserializes data into v like the package-level
+// UnmarshalPartial, using the codec's options, and returns the number of bytes
+// left after the value
+func (c Codec) UnmarshalPartial(data []byte, v interface{}) (remaining int, err error) {
+	if err := c.options.validate(); err != nil {
+		return len(data), err
+	}
+
+	// A BinaryUnmarshalerN reports how much of the data it consumed
+	if unmarshaler, ok := v.(BinaryUnmarshalerN); ok {
+		consumed, err := unmarshaler.UnmarshalBinaryN(data)
+		if err != nil {
+			return len(data), err
+		}
+		if consumed < 0 || consumed > len(data) {
+			return len(data), fmt.Errorf("UnmarshalBinaryN consumed %d bytes of %d", consumed, len(data))
+		}
+		return len(data) - consumed, nil
+	}
+
+	// Check if the value implements BinaryUnmarshaler
+	if unmarshaler, ok := asUnmarshaler(v); ok {
+		// For BinaryUnmarshaler, we consume all data and return 0 remaining
+		// This maintains compatibility with existing implementations
+		err = unmarshaler.UnmarshalBinary(data)
+		return 0, err
+	}
+
+	r := bytes.NewReader(data)
+	if err := decodeValue(v, &decodeState{Reader: r, codecOptions: c.options, maxAlloc: c.maxAlloc, ctx: c.ctx}); err != nil {
+		return r.Len(), err
+	}
+	return r.Len(), nil
+}
+
+// UnmarshalReader reads a single value from r into v like the package-level
+// UnmarshalReader, using the codec's options
+func (c Codec) UnmarshalReader(r io.Reader, v interface{}) error {
+	if err := c.options.validate(); err != nil {
+		return err
+	}
+	d := NewDecoder(r)
+	d.options = c.options
+	d.maxAlloc = c.maxAlloc
+	return d.Decode(v)
+}
@@ -0,0 +1,168 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecBigEndian(t *testing.T) {
+	type Message struct {
+		ID     uint32
+		Temp   int16
+		Ratio  float32
+		Name   string
+		Values []uint16
+		Grid   [2][2]int32
+		Short  string `binary:"prefix:2"`
+	}
+
+	codec := NewCodec().WithByteOrder(binary.BigEndian)
+	original := Message{
+		ID:     0x01020304,
+		Temp:   -2,
+		Ratio:  1,
+		Name:   "ab",
+		Values: []uint16{0x0102},
+		Grid:   [2][2]int32{{1, 2}, {3, 4}},
+		Short:  "c",
+	}
+
+	data, err := codec.Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		1, 2, 3, 4,
+		0xff, 0xfe,
+		0x3f, 0x80, 0, 0,
+		0, 0, 0, 2, 'a', 'b',
+		0, 0, 0, 1, 1, 2,
+		0, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0, 4,
+		0, 1, 'c',
+	}, data)
+
+	size, err := codec.Size(original)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), size)
+
+	var decoded Message
+	assert.NoError(t, codec.Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+
+	// The default codec reads the same bytes differently
+	var wrong Message
+	assert.Error(t, Unmarshal(data, &wrong))
+}
+
+func TestCodecBigEndianNestedTypes(t *testing.T) {
+	type Event struct {
+		At      time.Time
+		Shape   Shape
+		Custom  CustomType
+		Weights map[string]float64
+		Ptr     *uint64
+		Score   int
+	}
+
+	codec := NewCodec().WithByteOrder(binary.BigEndian)
+	n := uint64(9)
+	original := Event{
+		At:      time.Unix(1700000000, 5).UTC(),
+		Shape:   &Rect{Width: 1, Height: 2, Label: "r"},
+		Custom:  CustomType{Value: "x"},
+		Weights: map[string]float64{"a": 0.5, "b": 2},
+		Ptr:     &n,
+		Score:   -7,
+	}
+
+	data, err := codec.Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1700000000), binary.BigEndian.Uint64(data))
+
+	var decoded Event
+	assert.NoError(t, codec.Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+
+	assert.NoError(t, codec.UnmarshalReader(bytes.NewReader(data), &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestCodecBigEndianMapKeyOrder(t *testing.T) {
+	// Keys without a natural order are sorted by the bytes the codec writes
+	type Key struct{ ID uint16 }
+	m := map[Key]uint8{{ID: 1}: 1, {ID: 256}: 2}
+
+	data, err := NewCodec().WithByteOrder(binary.BigEndian).Marshal(m)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 2, 0, 1, 1, 1, 0, 2}, data)
+
+	data, err = Marshal(m)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2, 0, 0, 0, 0, 1, 2, 1, 0, 1}, data)
+}
+
+func TestCodecPrefixBytes(t *testing.T) {
+	type Message struct {
+		Name  string
+		Items []uint8
+		Wide  []byte `binary:"prefix:4"`
+	}
+
+	codec := NewCodec().WithPrefixBytes(1)
+	original := Message{Name: "hi", Items: []uint8{7}, Wide: []byte{1}}
+	data, err := codec.AppendTo([]byte{0xAA}, original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xAA, 2, 'h', 'i', 1, 7, 1, 0, 0, 0, 1}, data)
+
+	var decoded Message
+	remaining, err := codec.UnmarshalPartial(data[1:], &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, original, decoded)
+
+	_, err = codec.Marshal(Message{Name: string(make([]byte, 256))})
+	assert.Error(t, err)
+
+	invalid := NewCodec().WithPrefixBytes(3)
+	_, err = invalid.Marshal(original)
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	_, err = invalid.Size(original)
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	assert.ErrorIs(t, invalid.Unmarshal(data[1:], &decoded), ErrInvalidTag)
+}
+
+func TestCodecMaxAlloc(t *testing.T) {
+	codec := NewCodec().WithMaxAlloc(4)
+
+	data, err := codec.Marshal([]uint32{1, 2, 3, 4, 5})
+	assert.NoError(t, err)
+
+	var decoded []uint32
+	err = codec.Unmarshal(data, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds maximum allowed size of 4")
+	assert.Error(t, codec.UnmarshalReader(bytes.NewReader(data), &decoded))
+
+	// The limit does not affect other codecs
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, []uint32{1, 2, 3, 4, 5}, decoded)
+
+	var trailing uint8
+	assert.ErrorIs(t, codec.Unmarshal([]byte{1, 2}, &trailing), ErrTrailingData)
+}
+
+func TestDefaultCodecMatchesPackageFunctions(t *testing.T) {
+	value := map[string][]int32{"a": {1, -1}, "b": nil}
+
+	expected, err := Marshal(value)
+	assert.NoError(t, err)
+	data, err := Codec{}.Marshal(value)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, data)
+
+	explicit, err := NewCodec().WithByteOrder(binary.LittleEndian).WithPrefixBytes(4).Marshal(value)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, explicit)
+}
@@ -0,0 +1,61 @@
+package binary
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecodeComplex(t *testing.T) {
+	type ComplexStruct struct {
+		C64    complex64
+		C128   complex128
+		Vector []complex128
+		Pair   [2]complex64
+	}
+
+	original := ComplexStruct{
+		C64:    complex(1.5, -2.25),
+		C128:   complex(math.Pi, math.E),
+		Vector: []complex128{complex(1, 2), complex(-3.5, 0.125)},
+		Pair:   [2]complex64{complex(0.1, 0.2), complex(-0.3, 0.4)},
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// 8 + 16 + (4 + 2*16) + 2*8
+	assert.Len(t, data, 76)
+
+	var decoded ComplexStruct
+	assert.NoError(t, Unmarshal(data, &decoded))
+
+	// Use InEpsilon for floating point comparison of each part
+	assert.InEpsilon(t, real(original.C64), real(decoded.C64), 1e-6)
+	assert.InEpsilon(t, imag(original.C64), imag(decoded.C64), 1e-6)
+	assert.InEpsilon(t, real(original.C128), real(decoded.C128), 1e-15)
+	assert.InEpsilon(t, imag(original.C128), imag(decoded.C128), 1e-15)
+	assert.Len(t, decoded.Vector, 2)
+	for i := range original.Vector {
+		assert.InEpsilon(t, real(original.Vector[i]), real(decoded.Vector[i]), 1e-15)
+		assert.InEpsilon(t, imag(original.Vector[i]), imag(decoded.Vector[i]), 1e-15)
+	}
+	for i := range original.Pair {
+		assert.InEpsilon(t, real(original.Pair[i]), real(decoded.Pair[i]), 1e-6)
+		assert.InEpsilon(t, imag(original.Pair[i]), imag(decoded.Pair[i]), 1e-6)
+	}
+}
+
+func TestComplexWireFormat(t *testing.T) {
+	// The real part is written first, each part as a float of half the width
+	data, err := Marshal(complex64(complex(1, -1)))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x00, 0x80, 0x3f, 0x00, 0x00, 0x80, 0xbf}, data)
+
+	var decoded complex128
+	data, err = Marshal(complex(2.5, 0))
+	assert.NoError(t, err)
+	assert.Len(t, data, 16)
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, complex(2.5, 0), decoded)
+}
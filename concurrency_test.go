@@ -0,0 +1,66 @@
+package binary
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentMarshalUnmarshal(t *testing.T) {
+	type Record struct {
+		ID     uint32
+		Name   string
+		Values []uint16
+		Shape  Shape
+		Meta   map[string]uint8
+	}
+
+	const goroutines = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			original := Record{
+				ID:     uint32(i),
+				Name:   fmt.Sprintf("record-%d", i),
+				Values: []uint16{uint16(i), uint16(i * 2)},
+				Shape:  Circle{Radius: float64(i)},
+				Meta:   map[string]uint8{"i": uint8(i)},
+			}
+			for j := 0; j < 50; j++ {
+				data, err := Marshal(original)
+				if err != nil {
+					errs <- err
+					return
+				}
+				var decoded Record
+				if err := Unmarshal(data, &decoded); err != nil {
+					errs <- err
+					return
+				}
+				if decoded.ID != original.ID || decoded.Name != original.Name || decoded.Shape != original.Shape {
+					errs <- fmt.Errorf("goroutine %d decoded %+v", i, decoded)
+					return
+				}
+			}
+		}(i)
+	}
+
+	// Registering types concurrently with encoding is safe too
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		RegisterType(1000, [1]uint32{})
+	}()
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
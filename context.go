@@ -0,0 +1,40 @@
+package binary
+
+import "context"
+
+// cancelCheckInterval is the number of slice or map elements processed between
+// two checks of the context passed to MarshalContext or UnmarshalContext
+const cancelCheckInterval = 1024
+
+// MarshalContext is like Marshal but stops with the context's error once ctx is
+// canceled. The context is checked before encoding and then every 1024 elements
+// of each slice or map, so a canceled request does not keep encoding a large value.
+func MarshalContext(ctx context.Context, v interface{}) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c := defaultCodec
+	c.ctx = ctx
+	return c.Marshal(v)
+}
+
+// UnmarshalContext is like Unmarshal but stops with the context's error once
+// ctx is canceled, checking it like MarshalContext does
+func UnmarshalContext(ctx context.Context, data []byte, v interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c := defaultCodec
+	c.ctx = ctx
+	return c.Unmarshal(data, v)
+}
+
+// checkCanceled returns the error of ctx, if any, before the element at index i
+// of a slice or map is processed. It only looks at the context every
+// cancelCheckInterval elements to keep the overhead low.
+func checkCanceled(ctx context.Context, i int) error {
+	if ctx == nil || i%cancelCheckInterval != 0 {
+		return nil
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,86 @@
+package binary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// cancelAfter is a context that reports itself canceled once Err has been called n times
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestMarshalContextCanceledMidEncode(t *testing.T) {
+	type Item struct {
+		ID   uint32
+		Name string
+	}
+	items := make([]Item, 100_000)
+
+	// The first checks pass, so the cancellation hits the element loop
+	ctx := &cancelAfter{Context: context.Background(), n: 3}
+	_, err := MarshalContext(ctx, items)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, ctx.n, 0)
+
+	// Bulk slices are written in chunks so they notice the cancellation too
+	ctx = &cancelAfter{Context: context.Background(), n: 3}
+	_, err = MarshalContext(ctx, make([]uint32, 1_000_000))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	data, err := MarshalContext(context.Background(), items)
+	assert.NoError(t, err)
+	expected, err := Marshal(items)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, data)
+}
+
+func TestUnmarshalContextCanceledMidDecode(t *testing.T) {
+	type Holder struct {
+		Values []uint64
+		Names  []string
+		Index  map[uint32]string
+	}
+	original := Holder{
+		Values: make([]uint64, 10_000),
+		Names:  make([]string, 10_000),
+		Index:  make(map[uint32]string),
+	}
+	for i := 0; i < 10_000; i++ {
+		original.Index[uint32(i)] = "x"
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	for _, n := range []int{3, 15, 30} {
+		ctx := &cancelAfter{Context: context.Background(), n: n}
+		var decoded Holder
+		err = UnmarshalContext(ctx, data, &decoded)
+		assert.ErrorIs(t, err, context.Canceled, "after %d checks", n)
+	}
+
+	var decoded Holder
+	assert.NoError(t, UnmarshalContext(context.Background(), data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestContextAlreadyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := MarshalContext(ctx, uint8(1))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var v uint8
+	assert.ErrorIs(t, UnmarshalContext(ctx, []byte{1}, &v), context.Canceled)
+}
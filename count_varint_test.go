@@ -0,0 +1,78 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountVarintSizes(t *testing.T) {
+	type Default struct {
+		Values []uint8
+	}
+	type Compact struct {
+		Values []uint8 `binary:"countvarint"`
+	}
+
+	for _, tc := range []struct {
+		count      int
+		prefixSize int
+	}{
+		{5, 1},
+		{300, 2},
+	} {
+		values := make([]uint8, tc.count)
+		for i := range values {
+			values[i] = uint8(i)
+		}
+
+		defaultData, err := Marshal(Default{Values: values})
+		assert.NoError(t, err)
+		assert.Len(t, defaultData, 4+tc.count)
+
+		compactData, err := Marshal(Compact{Values: values})
+		assert.NoError(t, err)
+		assert.Len(t, compactData, tc.prefixSize+tc.count)
+
+		var decoded Compact
+		assert.NoError(t, Unmarshal(compactData, &decoded))
+		assert.Equal(t, values, decoded.Values)
+	}
+}
+
+func TestCountVarintNested(t *testing.T) {
+	type Record struct {
+		Names  []string          `binary:"countvarint"`
+		Counts map[string]uint16 `binary:"countvarint"`
+		Fixed  []uint16          `binary:"2"`
+	}
+
+	original := Record{Names: []string{"a", "bc"}, Counts: map[string]uint16{"x": 1}, Fixed: []uint16{1, 2}}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// The option also applies to the prefixes of nested strings, while
+	// fixed-count fields keep having no prefix at all
+	assert.Equal(t, []byte{
+		2, 1, 'a', 2, 'b', 'c',
+		1, 1, 'x', 1, 0,
+		1, 0, 2, 0,
+	}, data)
+
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestCountVarintConflicts(t *testing.T) {
+	type Invalid struct {
+		Values []uint8 `binary:"countvarint,prefix:2"`
+	}
+	_, err := Marshal(Invalid{})
+	assert.Error(t, err)
+
+	// A truncated varint prefix is an error
+	type Compact struct {
+		Values []uint8 `binary:"countvarint"`
+	}
+	assert.Error(t, Unmarshal([]byte{0x80}, &Compact{}))
+}
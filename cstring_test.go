@@ -0,0 +1,47 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cStringRecord struct {
+	Name string `binary:"cstr:8"`
+	ID   uint16
+}
+
+func TestCStringShort(t *testing.T) {
+	data, err := Marshal(cStringRecord{Name: "abc", ID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{'a', 'b', 'c', 0, 0, 0, 0, 0, 1, 0}, data)
+
+	var decoded cStringRecord
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, cStringRecord{Name: "abc", ID: 1}, decoded)
+
+	// The longest string that fits leaves one byte for the terminator
+	data, err = Marshal(cStringRecord{Name: "1234567"})
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0), data[7])
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, "1234567", decoded.Name)
+}
+
+func TestCStringFillingBuffer(t *testing.T) {
+	// A string filling the whole field leaves no room for the terminator
+	_, err := Marshal(cStringRecord{Name: "12345678"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not fit in a 8-byte C string")
+
+	_, err = Marshal(cStringRecord{Name: "a\x00b"})
+	assert.Error(t, err)
+}
+
+func TestCStringStopsAtFirstNUL(t *testing.T) {
+	// Bytes after the terminator are ignored, unlike plain fixed-length strings
+	data := []byte{'h', 'i', 0, 'x', 'y', 0, 0, 0, 2, 0}
+	var decoded cStringRecord
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, cStringRecord{Name: "hi", ID: 2}, decoded)
+}
@@ -0,0 +1,61 @@
+package binary
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomTypeArray(t *testing.T) {
+	original := [3]CustomType{{Value: "a"}, {Value: "bc"}, {Value: ""}}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	// Each element is written through MarshalBinary as length + data, like a struct field
+	assert.Equal(t, uint32(len("custom:a")), binary.LittleEndian.Uint32(data[0:4]))
+	assert.Equal(t, "custom:a", string(data[4:12]))
+
+	var decoded [3]CustomType
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestCustomTypeArrayField(t *testing.T) {
+	type Holder struct {
+		Items [3]CustomType
+		Tail  uint16
+	}
+
+	original := Holder{
+		Items: [3]CustomType{{Value: "x"}, {Value: "yy"}, {Value: "zzz"}},
+		Tail:  7,
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Len(t, data, 3*4+len("custom:x")+len("custom:yy")+len("custom:zzz")+2)
+
+	// Decoding into a reused destination replaces every element
+	decoded := Holder{Items: [3]CustomType{{Value: "old"}, {Value: "old"}, {Value: "old"}}}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestCustomTypeSliceAndMap(t *testing.T) {
+	type Holder struct {
+		List   []CustomType
+		ByName map[string]CustomType
+	}
+
+	original := Holder{
+		List:   []CustomType{{Value: "one"}, {Value: "two"}},
+		ByName: map[string]CustomType{"k": {Value: "v"}},
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Holder
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
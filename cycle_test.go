@@ -0,0 +1,64 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cycleNode struct {
+	Value uint32
+	Next  *cycleNode
+}
+
+func TestEncodeCycleDetected(t *testing.T) {
+	a := &cycleNode{Value: 1}
+	b := &cycleNode{Value: 2, Next: a}
+	a.Next = b
+
+	assert.NotPanics(t, func() {
+		_, err := Marshal(a)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cycle detected")
+		assert.Contains(t, err.Error(), "field Next")
+	})
+
+	// A node pointing to itself is the shortest cycle
+	self := &cycleNode{Value: 3}
+	self.Next = self
+	_, err := Marshal(self)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle detected")
+
+	// Cycles are also detected when the top-level value is not a pointer
+	_, err = Marshal(*a)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle detected")
+}
+
+func TestEncodeSharedPointerIsNotCycle(t *testing.T) {
+	type Pair struct {
+		Left  *cycleNode
+		Right *cycleNode
+	}
+	shared := &cycleNode{Value: 5}
+
+	// The same pointer reached twice without nesting is not a cycle
+	data, err := Marshal(Pair{Left: shared, Right: shared})
+	assert.NoError(t, err)
+
+	var decoded Pair
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, *shared, *decoded.Left)
+	assert.Equal(t, *shared, *decoded.Right)
+
+	// A pointer to a struct and to its first field share an address
+	type Outer struct {
+		Inner struct{ Value uint32 }
+		Ptr   *uint32
+	}
+	outer := &Outer{}
+	outer.Ptr = &outer.Inner.Value
+	_, err = Marshal(outer)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,151 @@
+package binary
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxDecimalScale is the largest scale whose multiplier 10^scale fits in an int64
+const maxDecimalScale = 18
+
+// encodeDecimalString serializes a decimal string as an int64 scaled by 10^scale
+func encodeDecimalString(s string, buf *encodeState, scale int) error {
+	v, err := parseDecimal(s, scale)
+	if err != nil {
+		return err
+	}
+	return binary.Write(buf, buf.byteOrder(), v)
+}
+
+// decodeDecimalString reads an int64 scaled by 10^scale and stores its decimal representation
+func decodeDecimalString(buf *decodeState, field reflect.Value, scale int) error {
+	var v int64
+	if err := binary.Read(buf, buf.byteOrder(), &v); err != nil {
+		return err
+	}
+	field.SetString(formatDecimal(v, scale))
+	return nil
+}
+
+// encodeScaledFloat serializes a float as an int64 scaled by 10^scale, rounding
+// it to scale fractional digits. Rounding goes through the exact decimal value
+// of the float, so 19.99 with scale 2 is stored as 1999.
+func encodeScaledFloat(v float64, buf *encodeState, scale int) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Errorf("cannot store %v as a fixed-point decimal", v)
+	}
+	return encodeDecimalString(strconv.FormatFloat(v, 'f', scale, 64), buf, scale)
+}
+
+// decodeScaledFloat reads an int64 scaled by 10^scale into a float field,
+// yielding the float nearest to the decimal value, e.g. 19.99 for 1999 with scale 2
+func decodeScaledFloat(buf *decodeState, field reflect.Value, scale int) error {
+	var v int64
+	if err := binary.Read(buf, buf.byteOrder(), &v); err != nil {
+		return err
+	}
+	f, err := strconv.ParseFloat(formatDecimal(v, scale), field.Type().Bits())
+	if err != nil {
+		return err
+	}
+	field.SetFloat(f)
+	return nil
+}
+
+// encodeDecimalFloat serializes a float as the shortest decimal string that
+// parses back to the same value, written like a string field
+func encodeDecimalFloat(v float64, bitSize int, buf *encodeState, tag string) error {
+	return encodeString(strconv.FormatFloat(v, 'g', -1, bitSize), buf, tag)
+}
+
+// decodeDecimalFloat reads a decimal string written by encodeDecimalFloat and parses it into a float field
+func decodeDecimalFloat(buf *decodeState, field reflect.Value, tag string) error {
+	s := reflect.New(reflect.TypeOf("")).Elem()
+	if err := decodeString(buf, s, tag); err != nil {
+		return err
+	}
+	v, err := strconv.ParseFloat(s.String(), field.Type().Bits())
+	if err != nil {
+		return fmt.Errorf("invalid decimal float %q", s.String())
+	}
+	field.SetFloat(v)
+	return nil
+}
+
+// parseDecimal converts a decimal string such as "-12.34" into an integer scaled by 10^scale.
+// An empty string is treated as zero. It returns an error if the string has more
+// fractional digits than the scale allows or if the result overflows an int64.
+func parseDecimal(s string, scale int) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	digits := s
+	negative := false
+	if digits[0] == '+' || digits[0] == '-' {
+		negative = digits[0] == '-'
+		digits = digits[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("invalid decimal string: %q", s)
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("invalid decimal string: %q", s)
+	}
+	if len(fracPart) > scale {
+		return 0, fmt.Errorf("decimal string %q has more than %d fractional digits", s, scale)
+	}
+
+	combined := intPart + fracPart + strings.Repeat("0", scale-len(fracPart))
+	if negative {
+		combined = "-" + combined
+	}
+	v, err := strconv.ParseInt(combined, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("decimal string %q out of range for scale %d", s, scale)
+	}
+	return v, nil
+}
+
+// formatDecimal renders an integer scaled by 10^scale as a decimal string with exactly scale fractional digits
+func formatDecimal(v int64, scale int) string {
+	negative := v < 0
+	abs := uint64(v)
+	if negative {
+		abs = -abs
+	}
+
+	digits := strconv.FormatUint(abs, 10)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	var sb strings.Builder
+	if negative {
+		sb.WriteByte('-')
+	}
+	if scale == 0 {
+		sb.WriteString(digits)
+		return sb.String()
+	}
+	sb.WriteString(digits[:len(digits)-scale])
+	sb.WriteByte('.')
+	sb.WriteString(digits[len(digits)-scale:])
+	return sb.String()
+}
+
+// isDigits reports whether s consists only of ASCII digits
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
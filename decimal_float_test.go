@@ -0,0 +1,78 @@
+package binary
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalFloatRoundTrip(t *testing.T) {
+	type Price struct {
+		Amount float64 `binary:"decimal"`
+		Rate   float32 `binary:"decimal"`
+		Raw    float64
+	}
+
+	original := Price{Amount: 0.1, Rate: 0.1, Raw: 0.1}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// "0.1" with a 4-byte prefix, twice, then the 8-byte IEEE-754 value
+	assert.Equal(t, []byte{3, 0, 0, 0, '0', '.', '1'}, data[:7])
+	assert.Len(t, data, 7+7+8)
+
+	var decoded Price
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, math.Float64bits(original.Amount), math.Float64bits(decoded.Amount))
+	assert.Equal(t, math.Float32bits(original.Rate), math.Float32bits(decoded.Rate))
+	assert.Equal(t, math.Float64bits(original.Raw), math.Float64bits(decoded.Raw))
+}
+
+func TestDecimalFloatExactBits(t *testing.T) {
+	type Value struct {
+		V float64 `binary:"decimal"`
+	}
+
+	values := []float64{
+		0.1, 0.2 + 0.1, 1e-300, math.MaxFloat64, math.SmallestNonzeroFloat64,
+		-123.456, math.Copysign(0, -1), math.Inf(1), math.Inf(-1),
+	}
+	for _, v := range values {
+		data, err := Marshal(Value{V: v})
+		assert.NoError(t, err)
+		var decoded Value
+		assert.NoError(t, Unmarshal(data, &decoded))
+		assert.Equal(t, math.Float64bits(v), math.Float64bits(decoded.V), "value %v", v)
+	}
+
+	data, err := Marshal(Value{V: math.NaN()})
+	assert.NoError(t, err)
+	var decoded Value
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.True(t, math.IsNaN(decoded.V))
+}
+
+func TestDecimalFloatElementsAndPrefix(t *testing.T) {
+	type Series struct {
+		Points []float64 `binary:"decimal,prefix:1"`
+	}
+
+	original := Series{Points: []float64{1.5, 0.3}}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// 1-byte count, then each element as a 1-byte length and its decimal string
+	assert.Equal(t, []byte{2, 3, '1', '.', '5', 3, '0', '.', '3'}, data)
+
+	var decoded Series
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestDecimalFloatInvalidInput(t *testing.T) {
+	type Value struct {
+		V float64 `binary:"decimal"`
+	}
+
+	var decoded Value
+	assert.Error(t, Unmarshal([]byte{3, 0, 0, 0, 'a', 'b', 'c'}, &decoded))
+}
@@ -0,0 +1,81 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Decimal string
+
+func TestDecimalStringScaleTag(t *testing.T) {
+	type Payment struct {
+		Amount Decimal `binary:"scale:2"`
+		Fee    string  `binary:"scale:4"`
+	}
+
+	original := Payment{Amount: "12.34", Fee: "-0.0005"}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Len(t, data, 16)
+
+	// The wire format is a plain little-endian int64 scaled by 10^scale
+	expected := new(bytes.Buffer)
+	binary.Write(expected, binary.LittleEndian, int64(1234))
+	binary.Write(expected, binary.LittleEndian, int64(-5))
+	assert.Equal(t, expected.Bytes(), data)
+
+	var decoded Payment
+	err = Unmarshal(data, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
+
+func TestDecimalStringCanonicalForm(t *testing.T) {
+	type Price struct {
+		Value string `binary:"scale:2"`
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "0.00"},
+		{"5", "5.00"},
+		{"1.5", "1.50"},
+		{"+.5", "0.50"},
+		{"-0.01", "-0.01"},
+		{"92233720368547758.07", "92233720368547758.07"},
+	}
+
+	for _, test := range tests {
+		data, err := Marshal(Price{Value: test.input})
+		assert.NoError(t, err, "input %q", test.input)
+
+		var decoded Price
+		err = Unmarshal(data, &decoded)
+		assert.NoError(t, err, "input %q", test.input)
+		assert.Equal(t, test.expected, decoded.Value, "input %q", test.input)
+	}
+}
+
+func TestDecimalStringInvalid(t *testing.T) {
+	type Price struct {
+		Value string `binary:"scale:2"`
+	}
+
+	for _, input := range []string{"1.234", "abc", "1.2.3", ".", "-", "92233720368547758.08"} {
+		_, err := Marshal(Price{Value: input})
+		assert.Error(t, err, "input %q", input)
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	assert.Equal(t, "0", formatDecimal(0, 0))
+	assert.Equal(t, "0.001", formatDecimal(1, 3))
+	assert.Equal(t, "-123.4", formatDecimal(-1234, 1))
+	assert.Equal(t, "-92233720368547758.08", formatDecimal(-9223372036854775808, 2))
+}
@@ -2,25 +2,47 @@ package binary
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"reflect"
+	"slices"
 )
 
 // Unmarshal deserializes binary data into a value
-// This function expects all data to be consumed and returns an error if there are remaining bytes
+// This function expects all data to be consumed and returns an error if there are remaining bytes,
+// unless v implements ForwardCompatible
 func Unmarshal(data []byte, v interface{}) error {
+	return defaultCodec.Unmarshal(data, v)
+}
+
+// UnmarshalStrict deserializes binary data into a value like Unmarshal and also
+// reports how many bytes were consumed. On success consumed equals len(data).
+// If bytes remain after the value, an error is returned and consumed is the
+// offset of the first unconsumed byte, which helps to locate framing bugs.
+func UnmarshalStrict(data []byte, v interface{}) (consumed int, err error) {
 	remaining, err := UnmarshalPartial(data, v)
+	consumed = len(data) - remaining
 	if err != nil {
-		return err
+		return consumed, err
 	}
 
-	// Check for remaining data - this maintains backward compatibility
 	if remaining > 0 {
-		return fmt.Errorf("warning: %d bytes of data remaining after unmarshaling", remaining)
+		return consumed, &TrailingDataError{Remaining: remaining}
 	}
 
-	return nil
+	return consumed, nil
+}
+
+// UnmarshalLenient deserializes binary data into a value and ignores any bytes
+// that remain after it, like UnmarshalPartial without the remaining count.
+// Use it for data that may carry trailing bytes this program does not know about.
+func UnmarshalLenient(data []byte, v interface{}) error {
+	_, err := UnmarshalPartial(data, v)
+	return err
 }
 
 // UnmarshalPartial deserializes binary data into a value and returns the number of remaining bytes
@@ -30,49 +52,231 @@ func Unmarshal(data []byte, v interface{}) error {
 //   - remaining: number of bytes left unprocessed in the input data
 //   - error: any error that occurred during unmarshaling
 func UnmarshalPartial(data []byte, v interface{}) (remaining int, err error) {
-	// Check if the value implements BinaryUnmarshaler
-	if unmarshaler, ok := v.(BinaryUnmarshaler); ok {
-		// For BinaryUnmarshaler, we consume all data and return 0 remaining
-		// This maintains compatibility with existing implementations
-		err = unmarshaler.UnmarshalBinary(data)
-		return 0, err
-	}
+	return defaultCodec.UnmarshalPartial(data, v)
+}
 
+// decodeValue deserializes a top-level value into the value pointed to by v
+func decodeValue(v interface{}, buf *decodeState) error {
 	val := reflect.ValueOf(v)
 
 	// Check if v is a pointer
 	if val.Kind() != reflect.Ptr {
-		return len(data), fmt.Errorf("only pointers are supported for unmarshaling")
+		return fmt.Errorf("only pointers are supported for unmarshaling")
 	}
 
 	// Check if v is a nil pointer
 	if val.IsNil() {
-		return len(data), fmt.Errorf("cannot unmarshal into nil pointer")
+		return fmt.Errorf("cannot unmarshal into %w", ErrNilPointer)
 	}
 
 	// Get the element that the pointer points to
 	elem := val.Elem()
+	if buf.clearBeforeDecode {
+		elem.SetZero()
+	}
+
+	// Nested top-level pointers carry no presence byte, matching Marshal
+	for elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			elem.Set(reflect.New(elem.Type().Elem()))
+		}
+		elem = elem.Elem()
+	}
+
+	// A top-level Raw value takes all remaining input
+	if elem.Type() == rawType {
+		return decodeRest(buf, elem)
+	}
 
-	// Unmarshal any type by calling decodeField directly
-	buf := bytes.NewReader(data)
 	if err := decodeField(buf, elem, ""); err != nil {
-		return buf.Len(), fmt.Errorf("error unmarshaling value: %w", err)
+		return fmt.Errorf("error unmarshaling value: %w", err)
+	}
+	return nil
+}
+
+// decodeState carries the input and decoding options through a single decoding pass
+type decodeState struct {
+	io.Reader
+	codecOptions
+	maxAlloc          int             // maximum length a length prefix may declare, 0 means no limit
+	zeroFillOnEOF     bool            // zero-fill fixed-length fields cut short by EOF instead of failing
+	clearBeforeDecode bool            // zero the destination before decoding into it
+	reuseMaps         bool            // clear and refill non-nil maps in the destination instead of replacing them
+	truncated         bool            // set when a fixed-length field was zero-filled
+	n                 int64           // number of bytes read so far
+	group             string          // if set, only fields without a group or in this group are decoded
+	depth             int             // nesting depth of the value being decoded
+	maxDepth          int             // maximum nesting depth, 0 means defaultMaxDepth
+	ctx               context.Context // if set, slices and maps stop decoding once it is canceled
+}
+
+// defaultMaxDepth is the default limit on the nesting depth of decoded values.
+// It keeps hostile input that implies very deep nesting, such as a long linked
+// list, from exhausting the goroutine stack.
+const defaultMaxDepth = 1000
+
+// enter increments the nesting depth when decoding a struct, slice, array, map
+// or interface, and fails once the depth exceeds the limit
+func (d *decodeState) enter() error {
+	limit := d.maxDepth
+	if limit <= 0 {
+		limit = defaultMaxDepth
+	}
+	if d.depth >= limit {
+		return fmt.Errorf("nesting depth exceeds maximum of %d", limit)
+	}
+	d.depth++
+	return nil
+}
+
+// leave decrements the nesting depth once a value entered with enter is decoded
+func (d *decodeState) leave() {
+	d.depth--
+}
+
+// Read reads from the underlying reader and keeps track of the number of bytes read.
+// Running out of input is reported as errUnexpectedEOF rather than io.EOF, since
+// a value is always expected; Decoder.Decode turns it back into io.EOF when the
+// input ended cleanly before the value.
+func (d *decodeState) Read(p []byte) (int, error) {
+	n, err := d.Reader.Read(p)
+	if err == io.EOF {
+		err = errUnexpectedEOF
+	}
+	d.n += int64(n)
+	return n, err
+}
+
+// remaining returns the number of unread input bytes if the reader can report it
+func (d *decodeState) remaining() (int, bool) {
+	if r, ok := d.Reader.(interface{ Len() int }); ok {
+		return r.Len(), true
 	}
+	return 0, false
+}
+
+// checkLength validates a length prefix declaring a number of bytes before it is allocated
+func (d *decodeState) checkLength(length int) error {
+	if err := d.checkCount(length); err != nil {
+		return err
+	}
+	if remaining, ok := d.remaining(); ok && length > remaining {
+		return fmt.Errorf("length %d exceeds remaining data of %d bytes: %w", length, remaining, errUnexpectedEOF)
+	}
+	return nil
+}
+
+// checkCount validates a length prefix declaring a number of elements against the configured limit
+func (d *decodeState) checkCount(length int) error {
+	if d.maxAlloc > 0 && length > d.maxAlloc {
+		return fmt.Errorf("length %d exceeds maximum allowed size of %d", length, d.maxAlloc)
+	}
+	return nil
+}
 
-	// Return the number of remaining bytes
-	return buf.Len(), nil
+// maxUnboundedAlloc is the number of bytes allocated up front for a length prefix
+// when the reader cannot report the remaining input, such as a stream. Larger
+// values grow as their data arrives, so that a corrupt or hostile prefix fails at
+// the end of the input instead of allocating the declared length.
+const maxUnboundedAlloc = 1 << 20
+
+// initialLen returns how many elements of a slice with the declared length can be
+// allocated up front. Each element with a non-zero size occupies at least one byte,
+// so a length larger than the remaining input is capped and the slice grows as
+// elements are actually decoded. When the remaining input is unknown, the
+// elements allocated up front are limited to maxUnboundedAlloc bytes.
+func (d *decodeState) initialLen(length int, elemType reflect.Type) int {
+	size := int(elemType.Size())
+	if size == 0 {
+		return length
+	}
+	remaining, ok := d.remaining()
+	if !ok {
+		remaining = max(maxUnboundedAlloc/size, 1)
+	}
+	return min(length, remaining)
+}
+
+// readBytes reads length bytes after checkLength has accepted the length.
+// On readers that cannot report the remaining input, lengths above
+// maxUnboundedAlloc are read into a growing buffer.
+func (d *decodeState) readBytes(length int) ([]byte, error) {
+	if _, ok := d.remaining(); ok || length <= maxUnboundedAlloc {
+		data := make([]byte, length)
+		return data, d.readFull(data)
+	}
+	var data bytes.Buffer
+	n, err := io.CopyN(&data, d, int64(length))
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("read %d of %d bytes: %w", n, length, errUnexpectedEOF)
+	}
+	return data.Bytes(), err
+}
+
+// ReadByte reads a single byte, allowing the decode state to be used as an io.ByteReader
+func (d *decodeState) ReadByte() (byte, error) {
+	var data [1]byte
+	if _, err := io.ReadFull(d, data[:]); err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+// readFull reads exactly len(data) bytes, returning a wrapped errUnexpectedEOF
+// if the input ends before all of them are available
+func (d *decodeState) readFull(data []byte) error {
+	_, err := d.readAvailable(data)
+	return err
+}
+
+// readFixed reads exactly len(data) bytes for a fixed-length field.
+// With zeroFillOnEOF enabled, a field cut short by the end of input keeps the
+// bytes that were available and the rest of data is zero-filled.
+func (d *decodeState) readFixed(data []byte) error {
+	n, err := d.readAvailable(data)
+	if err != nil && d.zeroFillOnEOF && errors.Is(err, io.ErrUnexpectedEOF) {
+		clear(data[n:])
+		d.truncated = true
+		return nil
+	}
+	return err
+}
+
+// readAvailable reads len(data) bytes and returns how many were read before any error
+func (d *decodeState) readAvailable(data []byte) (int, error) {
+	n, err := io.ReadFull(d, data)
+	if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, fmt.Errorf("read %d of %d bytes: %w", n, len(data), errUnexpectedEOF)
+	}
+	return n, err
 }
 
 // decodeField handles deserialization of a single field
-func decodeField(buf *bytes.Reader, field reflect.Value, tag string) error {
+func decodeField(buf *decodeState, field reflect.Value, tag string) error {
 	// If tag is "-", skip this field entirely (consistent with struct behavior)
 	if tag == "-" {
 		return nil
 	}
 
+	if hasTagOption(tag, "varint") && isIntegerKind(field.Kind()) {
+		return decodeVarint(buf, field)
+	}
+
 	switch field.Kind() {
 	case reflect.Ptr:
-		// Handle pointer types by dereferencing them
+		// Pointers are written as a presence byte (0 = nil, 1 = present) followed by the value
+		present, err := buf.ReadByte()
+		if err != nil {
+			return err
+		}
+		switch present {
+		case 0:
+			field.SetZero()
+			return nil
+		case 1:
+		default:
+			return fmt.Errorf("invalid pointer presence byte: %d", present)
+		}
 		if field.IsNil() {
 			// Create a new instance of the pointed-to type
 			newValue := reflect.New(field.Type().Elem())
@@ -81,14 +285,14 @@ func decodeField(buf *bytes.Reader, field reflect.Value, tag string) error {
 		return decodeField(buf, field.Elem(), tag)
 
 	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
-		reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int, reflect.Bool:
+		reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Bool:
 		// For basic numeric types, we need to pass a pointer to binary.Read
 		if field.CanAddr() {
-			return binary.Read(buf, binary.LittleEndian, field.Addr().Interface())
+			return binary.Read(buf, buf.byteOrder(), field.Addr().Interface())
 		} else {
 			// For non-addressable values (like array elements), we need to read into a temporary variable
 			temp := reflect.New(field.Type()).Elem()
-			err := binary.Read(buf, binary.LittleEndian, temp.Addr().Interface())
+			err := binary.Read(buf, buf.byteOrder(), temp.Addr().Interface())
 			if err != nil {
 				return err
 			}
@@ -96,14 +300,56 @@ func decodeField(buf *bytes.Reader, field reflect.Value, tag string) error {
 			return nil
 		}
 
-	case reflect.Float32, reflect.Float64:
+	case reflect.Int:
+		// int is always encoded as 8 bytes, decode it into the platform-native width
+		var v int64
+		if err := binary.Read(buf, buf.byteOrder(), &v); err != nil {
+			return err
+		}
+		if field.OverflowInt(v) {
+			return fmt.Errorf("value %d overflows %s", v, field.Type())
+		}
+		field.SetInt(v)
+		return nil
+
+	case reflect.Uint, reflect.Uintptr:
+		// uint and uintptr are always encoded as 8 bytes, decode them into the platform-native width
+		var v uint64
+		if err := binary.Read(buf, buf.byteOrder(), &v); err != nil {
+			return err
+		}
+		if field.OverflowUint(v) {
+			return fmt.Errorf("value %d overflows %s", v, field.Type())
+		}
+		field.SetUint(v)
+		return nil
+
+	case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		if field.Kind() == reflect.Float32 || field.Kind() == reflect.Float64 {
+			if info, err := parseTag(tag); err == nil {
+				if info.HasScale {
+					return decodeScaledFloat(buf, field, info.Scale)
+				}
+				if info.Decimal {
+					return decodeDecimalFloat(buf, field, tag)
+				}
+				if info.Float32 && field.Kind() == reflect.Float64 {
+					var v float32
+					if err := binary.Read(buf, buf.byteOrder(), &v); err != nil {
+						return err
+					}
+					field.SetFloat(float64(v))
+					return nil
+				}
+			}
+		}
 		// For basic numeric types, we need to pass a pointer to binary.Read
 		if field.CanAddr() {
-			return binary.Read(buf, binary.LittleEndian, field.Addr().Interface())
+			return binary.Read(buf, buf.byteOrder(), field.Addr().Interface())
 		} else {
 			// For non-addressable values (like array elements), we need to read into a temporary variable
 			temp := reflect.New(field.Type()).Elem()
-			err := binary.Read(buf, binary.LittleEndian, temp.Addr().Interface())
+			err := binary.Read(buf, buf.byteOrder(), temp.Addr().Interface())
 			if err != nil {
 				return err
 			}
@@ -112,9 +358,38 @@ func decodeField(buf *bytes.Reader, field reflect.Value, tag string) error {
 		}
 
 	case reflect.String:
+		if info, err := parseTag(tag); err == nil && info.HasScale {
+			return decodeDecimalString(buf, field, info.Scale)
+		} else if err == nil && info.UTF16 {
+			return decodeUTF16String(buf, field, tag)
+		}
 		return decodeString(buf, field, tag)
 
 	case reflect.Slice:
+		if field.Type() == rawType {
+			if err := checkRawTag(tag); err != nil {
+				return err
+			}
+		}
+		if hasNilPresence(buf.codecOptions, field.Type()) {
+			if isNil, err := decodeNilPresence(buf, field); isNil || err != nil {
+				return err
+			}
+		}
+		if hasTagOption(tag, packedTag) {
+			return decodePackedBools(buf, field, tag)
+		}
+		if hasTagOption(tag, runesTag) && field.Type().Elem().Kind() != reflect.Slice {
+			return decodeRunes(buf, field, tag)
+		}
+		if field.Type() == ipType {
+			ip, err := decodeIP(buf)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(ip))
+			return nil
+		}
 		if field.Type().Elem().Kind() == reflect.Uint8 {
 			// []byte
 			return decodeBytes(buf, field, tag)
@@ -130,40 +405,118 @@ func decodeField(buf *bytes.Reader, field reflect.Value, tag string) error {
 		// Other arrays
 		return decodeArray(buf, field, tag)
 
+	case reflect.Map:
+		if hasNilPresence(buf.codecOptions, field.Type()) {
+			if isNil, err := decodeNilPresence(buf, field); isNil || err != nil {
+				return err
+			}
+		}
+		return decodeMap(buf, field, tag)
+
 	case reflect.Struct:
+		if field.Type() == timeType {
+			return decodeTime(buf, field)
+		}
+		if field.Type() == ipNetType {
+			return decodeIPNet(buf, field)
+		}
+		if field.Type() == bigIntType {
+			return decodeBigInt(buf, field, tag)
+		}
+		if field.Type() == bigRatType {
+			return decodeBigRat(buf, field, tag)
+		}
+		if usesCustomCodec(field.Type()) {
+			return decodeCustom(buf, field, field.Type())
+		}
 		return decodeStruct(buf, field)
 
+	case reflect.Interface:
+		return decodeInterface(buf, field, tag)
+
 	default:
-		return fmt.Errorf("unsupported type: %s", field.Kind())
+		return &UnsupportedTypeError{Kind: field.Kind()}
 	}
 }
 
+// readLength reads the length prefix of a variable-length field using the
+// width and byte order selected by the tag
+func readLength(buf *decodeState, tag string) (int, error) {
+	info, err := parseTag(tag)
+	if err != nil {
+		return 0, err
+	}
+	prefix := info.prefix(buf.codecOptions)
+
+	var length uint64
+	if prefix.varint {
+		if length, err = binary.ReadUvarint(buf); err != nil {
+			return 0, err
+		}
+		if length > math.MaxInt {
+			return 0, fmt.Errorf("length %d exceeds maximum supported length", length)
+		}
+		return int(length), nil
+	}
+
+	var data [8]byte
+	if err := buf.readFull(data[:prefix.width]); err != nil {
+		return 0, err
+	}
+
+	switch prefix.width {
+	case 1:
+		length = uint64(data[0])
+	case 2:
+		length = uint64(prefix.order.Uint16(data[:]))
+	case 4:
+		length = uint64(prefix.order.Uint32(data[:]))
+	case 8:
+		length = prefix.order.Uint64(data[:])
+	}
+	if length > math.MaxInt {
+		return 0, fmt.Errorf("length %d exceeds maximum supported length", length)
+	}
+	return int(length), nil
+}
+
 // decodeString handles deserialization of strings
-func decodeString(buf *bytes.Reader, field reflect.Value, tag string) error {
+func decodeString(buf *decodeState, field reflect.Value, tag string) error {
 	var data []byte
 	var err error
 
 	// Check if tag specifies length
 	if tag != "" {
-		if length, parseErr := parseTag(tag); parseErr == nil {
+		if info, parseErr := parseTag(tag); parseErr == nil && info.HasFixedLen {
+			length := info.FixedLen
 			if length == 0 {
 				field.SetString("")
 				return nil
 			}
 			data = make([]byte, length)
-			if _, err = buf.Read(data); err != nil {
+			if err = buf.readFixed(data); err != nil {
 				return err
 			}
-			// Trim trailing zeros
-			data = bytes.TrimRight(data, "\x00")
+			if info.CString {
+				// C strings end at the first NUL, whatever follows it in the field
+				if i := bytes.IndexByte(data, 0); i >= 0 {
+					data = data[:i]
+				}
+				field.SetString(string(data))
+				return nil
+			}
+			// Trim trailing zeros, unless the field keeps all of its bytes
+			if !info.Raw {
+				data = bytes.TrimRight(data, "\x00")
+			}
 			field.SetString(string(data))
 			return nil
 		}
 	}
 
 	// Default format: len(data) + data
-	var length uint32
-	if err = binary.Read(buf, binary.LittleEndian, &length); err != nil {
+	length, err := readLength(buf, tag)
+	if err != nil {
 		return err
 	}
 
@@ -173,8 +526,10 @@ func decodeString(buf *bytes.Reader, field reflect.Value, tag string) error {
 		return nil
 	}
 
-	data = make([]byte, length)
-	if _, err = buf.Read(data); err != nil {
+	if err = buf.checkLength(length); err != nil {
+		return err
+	}
+	if data, err = buf.readBytes(length); err != nil {
 		return err
 	}
 
@@ -183,19 +538,20 @@ func decodeString(buf *bytes.Reader, field reflect.Value, tag string) error {
 }
 
 // decodeBytes handles deserialization of []byte
-func decodeBytes(buf *bytes.Reader, field reflect.Value, tag string) error {
+func decodeBytes(buf *decodeState, field reflect.Value, tag string) error {
 	var data []byte
 	var err error
 
 	// Check if tag specifies length
 	if tag != "" {
-		if length, parseErr := parseTag(tag); parseErr == nil {
+		if info, parseErr := parseTag(tag); parseErr == nil && info.HasFixedLen {
+			length := info.FixedLen
 			if length == 0 {
 				field.SetBytes([]byte{})
 				return nil
 			}
 			data = make([]byte, length)
-			if _, err = buf.Read(data); err != nil {
+			if err = buf.readFixed(data); err != nil {
 				return err
 			}
 			field.SetBytes(data)
@@ -204,8 +560,8 @@ func decodeBytes(buf *bytes.Reader, field reflect.Value, tag string) error {
 	}
 
 	// Default format: len(data) + data
-	var length uint32
-	if err = binary.Read(buf, binary.LittleEndian, &length); err != nil {
+	length, err := readLength(buf, tag)
+	if err != nil {
 		return err
 	}
 
@@ -215,8 +571,10 @@ func decodeBytes(buf *bytes.Reader, field reflect.Value, tag string) error {
 		return nil
 	}
 
-	data = make([]byte, length)
-	if _, err = buf.Read(data); err != nil {
+	if err = buf.checkLength(length); err != nil {
+		return err
+	}
+	if data, err = buf.readBytes(length); err != nil {
 		return err
 	}
 
@@ -225,23 +583,29 @@ func decodeBytes(buf *bytes.Reader, field reflect.Value, tag string) error {
 }
 
 // decodeByteArray handles deserialization of [N]byte
-func decodeByteArray(buf *bytes.Reader, field reflect.Value, tag string) error {
+func decodeByteArray(buf *decodeState, field reflect.Value, tag string) error {
 	var data []byte
 	var err error
 
 	// Check if tag specifies length
 	if tag != "" {
-		if length, parseErr := parseTag(tag); parseErr == nil {
+		if info, parseErr := parseTag(tag); parseErr == nil && info.HasFixedLen {
+			length := info.FixedLen
 			data = make([]byte, length)
-			if _, err = buf.Read(data); err != nil {
+			if err = buf.readFixed(data); err != nil {
 				return err
 			}
 
-			// Copy data to array, truncating or padding as necessary
+			// Copy data to array, truncating or padding as necessary. Encoding pads
+			// an array shorter than its tag with zeros, so any other bytes beyond
+			// the array's size cannot be stored and are reported instead of dropped.
 			arrayLen := field.Len()
 			copyLen := len(data)
 			if copyLen > arrayLen {
 				copyLen = arrayLen
+				if i := slices.IndexFunc(data[arrayLen:], func(b byte) bool { return b != 0 }); i >= 0 {
+					return fmt.Errorf("non-zero byte at offset %d of a %d-byte field does not fit in %s", arrayLen+i, length, field.Type())
+				}
 			}
 
 			// Copy data to array
@@ -259,8 +623,8 @@ func decodeByteArray(buf *bytes.Reader, field reflect.Value, tag string) error {
 	}
 
 	// Default format: len(data) + data
-	var length uint32
-	if err = binary.Read(buf, binary.LittleEndian, &length); err != nil {
+	length, err := readLength(buf, tag)
+	if err != nil {
 		return err
 	}
 
@@ -274,8 +638,10 @@ func decodeByteArray(buf *bytes.Reader, field reflect.Value, tag string) error {
 		return nil
 	}
 
-	data = make([]byte, length)
-	if _, err = buf.Read(data); err != nil {
+	if err = buf.checkLength(length); err != nil {
+		return err
+	}
+	if data, err = buf.readBytes(length); err != nil {
 		return err
 	}
 
@@ -300,10 +666,16 @@ func decodeByteArray(buf *bytes.Reader, field reflect.Value, tag string) error {
 }
 
 // decodeSlice handles deserialization of slices (except []byte)
-func decodeSlice(buf *bytes.Reader, field reflect.Value, tag string) error {
+func decodeSlice(buf *decodeState, field reflect.Value, tag string) error {
+	if err := buf.enter(); err != nil {
+		return err
+	}
+	defer buf.leave()
+
 	// Check if tag specifies length
 	if tag != "" {
-		if length, err := parseTag(tag); err == nil {
+		if info, err := parseTag(tag); err == nil && info.HasFixedLen {
+			length := info.FixedLen
 			// Get slice type and element type
 			sliceType := field.Type()
 
@@ -315,15 +687,21 @@ func decodeSlice(buf *bytes.Reader, field reflect.Value, tag string) error {
 			}
 
 			// For fixed-length slices, we don't read a length prefix
-			// Create slice with the specified fixed length
-			newSlice := reflect.MakeSlice(sliceType, int(length), int(length))
+			// Create slice with the specified fixed length, reusing the destination if it has room
+			newSlice, ok := reuseSlice(field, int(length))
+			if !ok {
+				newSlice = reflect.MakeSlice(sliceType, int(length), int(length))
+			}
 
 			// Read elements directly
 			for i := uint32(0); i < length; i++ {
-				elem := newSlice.Index(int(i))
-				if err := decodeField(buf, elem, ""); err != nil {
+				if err := checkCanceled(buf.ctx, int(i)); err != nil {
 					return err
 				}
+				elem := newSlice.Index(int(i))
+				if err := decodeField(buf, elem, elementTag(tag)); err != nil {
+					return wrapFieldError("decoding", indexSegment(i), err)
+				}
 			}
 
 			field.Set(newSlice)
@@ -332,44 +710,79 @@ func decodeSlice(buf *bytes.Reader, field reflect.Value, tag string) error {
 	}
 
 	// Default format: len(slice) + elements
-	var length uint32
-	if err := binary.Read(buf, binary.LittleEndian, &length); err != nil {
+	length, err := readLength(buf, tag)
+	if err != nil {
+		return err
+	}
+
+	if err := buf.checkCount(length); err != nil {
 		return err
 	}
 
-	// Create slice
+	if isBulkSlice(field.Type(), elementTag(tag)) {
+		return decodeBulkSlice(buf, field, length)
+	}
+
+	// Reuse the destination if it has room, otherwise create a slice,
+	// never allocating more elements up front than the input can hold
 	sliceType := field.Type()
-	newSlice := reflect.MakeSlice(sliceType, int(length), int(length))
+	newSlice, ok := reuseSlice(field, length)
+	if !ok {
+		initialLen := buf.initialLen(length, sliceType.Elem())
+		newSlice = reflect.MakeSlice(sliceType, initialLen, initialLen)
+	}
 
 	// Read each element
 	for i := 0; i < int(length); i++ {
-		elem := newSlice.Index(i)
-		if err := decodeField(buf, elem, ""); err != nil {
+		if err := checkCanceled(buf.ctx, i); err != nil {
 			return err
 		}
+		if i == newSlice.Len() {
+			newSlice = reflect.Append(newSlice, reflect.Zero(sliceType.Elem()))
+		}
+		elem := newSlice.Index(i)
+		if err := decodeField(buf, elem, elementTag(tag)); err != nil {
+			return wrapFieldError("decoding", indexSegment(i), err)
+		}
 	}
 
 	field.Set(newSlice)
 	return nil
 }
 
+// reuseSlice reslices a destination slice to length when its backing array is
+// large enough, so that decoding repeatedly into the same variable does not
+// allocate. The reused elements are zeroed first so that nothing from a
+// previous value survives.
+func reuseSlice(field reflect.Value, length int) (reflect.Value, bool) {
+	if field.Cap() == 0 || field.Cap() < length {
+		return reflect.Value{}, false
+	}
+	slice := field.Slice(0, length)
+	for i := 0; i < length; i++ {
+		slice.Index(i).SetZero()
+	}
+	return slice, true
+}
+
 // decodeArray handles deserialization of arrays (except [N]byte)
-func decodeArray(buf *bytes.Reader, field reflect.Value, tag string) error {
+func decodeArray(buf *decodeState, field reflect.Value, tag string) error {
+	if err := buf.enter(); err != nil {
+		return err
+	}
+	defer buf.leave()
+
 	// Check if tag specifies length
 	if tag != "" {
-		if length, err := parseTag(tag); err == nil {
+		if info, err := parseTag(tag); err == nil && info.HasFixedLen {
+			length := info.FixedLen
 			// Get array type and length
 			arrayType := field.Type()
 			arrayLen := uint32(arrayType.Len())
 
-			// Handle zero-length arrays
-			if length == 0 {
-				// Zero out all elements
-				for i := 0; i < int(arrayLen); i++ {
-					field.Index(i).Set(reflect.Zero(arrayType.Elem()))
-				}
-				return nil
-			}
+			// Zero the whole array first, so that elements beyond the encoded
+			// length never keep data from a reused destination
+			field.SetZero()
 
 			// For fixed-length arrays, we don't read a length prefix
 			// Read elements directly
@@ -377,29 +790,27 @@ func decodeArray(buf *bytes.Reader, field reflect.Value, tag string) error {
 				if i < arrayLen {
 					// Read actual element into array
 					elem := field.Index(int(i))
-					if err := decodeField(buf, elem, ""); err != nil {
-						return err
+					if err := decodeField(buf, elem, elementTag(tag)); err != nil {
+						return wrapFieldError("decoding", indexSegment(i), err)
 					}
 				} else {
 					// Skip extra elements by reading into a temporary value
 					temp := reflect.New(arrayType.Elem()).Elem()
-					if err := decodeField(buf, temp, ""); err != nil {
-						return err
+					if err := decodeField(buf, temp, elementTag(tag)); err != nil {
+						return wrapFieldError("decoding", indexSegment(i), err)
 					}
 				}
 			}
 
-			// Zero out remaining elements if data is shorter than array
-			for i := length; i < arrayLen; i++ {
-				field.Index(int(i)).Set(reflect.Zero(arrayType.Elem()))
-			}
-
 			return nil
 		}
 	}
 
 	// For arrays without tags, we also don't read a length prefix
 	// because the length is fixed and known at compile time
+	if isBulkArray(field.Type(), elementTag(tag)) {
+		return decodeBulkArray(buf, field)
+	}
 	arrayType := field.Type()
 	arrayLen := uint32(arrayType.Len())
 
@@ -407,20 +818,89 @@ func decodeArray(buf *bytes.Reader, field reflect.Value, tag string) error {
 	for i := uint32(0); i < arrayLen; i++ {
 		// Read actual element into array
 		elem := field.Index(int(i))
-		if err := decodeField(buf, elem, ""); err != nil {
+		if err := decodeField(buf, elem, elementTag(tag)); err != nil {
+			return wrapFieldError("decoding", indexSegment(i), err)
+		}
+	}
+
+	return nil
+}
+
+// decodeMap handles deserialization of maps written as len(map) + key/value pairs
+func decodeMap(buf *decodeState, field reflect.Value, tag string) error {
+	if err := buf.enter(); err != nil {
+		return err
+	}
+	defer buf.leave()
+
+	length, err := readLength(buf, tag)
+	if err != nil {
+		return err
+	}
+	if err := buf.checkCount(length); err != nil {
+		return err
+	}
+
+	mapType := field.Type()
+	var newMap reflect.Value
+	if buf.reuseMaps && !field.IsNil() {
+		// Keep the existing map and its buckets, but none of its entries
+		newMap = field
+		newMap.Clear()
+	} else {
+		newMap = reflect.MakeMapWithSize(mapType, buf.initialLen(length, mapType.Key()))
+	}
+
+	// SetMapIndex copies the key and value, so the same variables serve every entry
+	key := reflect.New(mapType.Key()).Elem()
+	value := reflect.New(mapType.Elem()).Elem()
+	for i := 0; i < int(length); i++ {
+		if err := checkCanceled(buf.ctx, i); err != nil {
 			return err
 		}
+		key.SetZero()
+		// A key that fails to decode is unknown, so it is identified by its position
+		if err := decodeField(buf, key, elementTag(tag)); err != nil {
+			return wrapFieldError("decoding", indexSegment(i), err)
+		}
+		// An interface key may hold a registered type that cannot be hashed
+		if mapType.Key().Kind() == reflect.Interface && !key.Comparable() {
+			return wrapFieldError("decoding", indexSegment(i), fmt.Errorf("map key of type %s is not comparable", key.Elem().Type()))
+		}
+		value.SetZero()
+		if err := decodeField(buf, value, elementTag(tag)); err != nil {
+			return wrapFieldError("decoding", indexSegment(key), err)
+		}
+		newMap.SetMapIndex(key, value)
 	}
 
+	field.Set(newMap)
 	return nil
 }
 
 // decodeStruct handles deserialization of a struct
-func decodeStruct(buf *bytes.Reader, val reflect.Value) error {
+func decodeStruct(buf *decodeState, val reflect.Value) error {
+	trailing := buf.depth == 0
+	if err := buf.enter(); err != nil {
+		return err
+	}
+	defer buf.leave()
+
+	if err := decodeStructFields(buf, val, trailing); err != nil {
+		return err
+	}
+	return callPostUnmarshal(val)
+}
+
+// decodeStructFields reads the fields of a struct in declaration order.
+// Embedded structs are flattened, so their fields are read inline at the parent level.
+// trailing reports whether the struct ends the top-level value, which is the
+// only place a "rest" or Raw field may appear.
+func decodeStructFields(buf *decodeState, val reflect.Value, trailing bool) error {
 	typ := val.Type()
-	numField := val.NumField()
+	var bits bitReader
 
-	for i := 0; i < numField; i++ {
+	for _, i := range fieldOrder(typ) {
 		field := val.Field(i)
 		fieldType := typ.Field(i)
 
@@ -429,41 +909,109 @@ func decodeStruct(buf *bytes.Reader, val reflect.Value) error {
 			continue
 		}
 
-		// Check if field implements BinaryUnmarshaler
-		if field.Kind() == reflect.Struct {
-			// Create a pointer to the field for interface check
-			fieldPtr := reflect.New(field.Type())
-			fieldPtr.Elem().Set(field)
+		included, tag := inGroup(fieldType.Tag.Get("binary"), buf.group)
+		if !included {
+			continue
+		}
+		tag, err := withoutOrderOption(tag)
+		if err != nil {
+			return wrapFieldError("decoding", fieldType.Name, err)
+		}
 
-			if unmarshaler, ok := fieldPtr.Interface().(BinaryUnmarshaler); ok {
-				// Read length
-				var length uint32
-				if err := binary.Read(buf, binary.LittleEndian, &length); err != nil {
-					return err
-				}
-				// Read data
-				data := make([]byte, length)
-				if _, err := buf.Read(data); err != nil {
-					return err
-				}
-				// Unmarshal the field
-				if err := unmarshaler.UnmarshalBinary(data); err != nil {
-					return fmt.Errorf("error unmarshaling field %s: %w", fieldType.Name, err)
-				}
-				// Set the field
-				field.Set(fieldPtr.Elem())
-				continue
+		// Consecutive bool fields tagged "bits" share bytes
+		if tag == bitsTag {
+			if err := bits.readBit(buf, field); err != nil {
+				return wrapFieldError("decoding", fieldType.Name, err)
 			}
+			continue
 		}
+		bits.reset()
 
-		tag := fieldType.Tag.Get("binary")
-		// If tag is "-", skip this field entirely
+		// If tag is "-", skip this field entirely, even if it has its own unmarshaler
 		if tag == "-" {
 			continue
 		}
 
+		if field.Kind() == reflect.Ptr && usesCustomCodec(field.Type().Elem()) {
+			if err := decodeCustomPointer(buf, field); err != nil {
+				return wrapFieldError("unmarshaling", fieldType.Name, err)
+			}
+			continue
+		}
+
+		// Check if field implements BinaryUnmarshaler, which it can only do on a
+		// pointer receiver for the decoded value to be kept
+		if unmarshaler, ok := fieldUnmarshaler(field); ok {
+			// Read length
+			var length uint32
+			if err := binary.Read(buf, buf.byteOrder(), &length); err != nil {
+				return err
+			}
+			// Read data
+			if err := buf.checkLength(int(length)); err != nil {
+				return err
+			}
+			data, err := buf.readBytes(int(length))
+			if err != nil {
+				return err
+			}
+			// Unmarshal the field in place
+			if err := unmarshaler.UnmarshalBinary(data); err != nil {
+				return wrapFieldError("unmarshaling", fieldType.Name, err)
+			}
+			continue
+		}
+
+		// A trailing "rest" field takes all remaining input
+		if isRestField(fieldType, tag) {
+			if err := checkRestField(val, i, trailing, buf.group); err != nil {
+				return wrapFieldError("decoding", fieldType.Name, err)
+			}
+			if err := decodeRest(buf, field); err != nil {
+				return wrapFieldError("decoding", fieldType.Name, err)
+			}
+			continue
+		}
+
+		// Embedded structs are flattened into the parent
+		if isEmbeddedStruct(fieldType) {
+			if err := decodeStructFields(buf, field, trailing && isLastField(val.Type(), i, buf.group)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Fields tagged "omitempty" start with a presence byte, the value follows only if it is 1
+		if hasTagOption(tag, "omitempty") {
+			tag = withoutTagOption(tag, "omitempty")
+			present, err := buf.ReadByte()
+			if err != nil {
+				return wrapFieldError("decoding", fieldType.Name, err)
+			}
+			if present > 1 {
+				return wrapFieldError("decoding", fieldType.Name, fmt.Errorf("invalid presence byte: %d", present))
+			}
+			if present == 0 {
+				field.SetZero()
+				continue
+			}
+		}
+
+		// Fields tagged "lenfrom" take their length from an earlier field
+		if name, ok := lenFromOption(tag); ok {
+			source, err := lenFromSource(typ, i, name)
+			if err != nil {
+				return wrapFieldError("decoding", fieldType.Name, err)
+			}
+			length, err := decodedLength(buf, val.Field(source), field)
+			if err != nil {
+				return wrapFieldError("decoding", fieldType.Name, err)
+			}
+			tag = withFixedLength(tag, length)
+		}
+
 		if err := decodeField(buf, field, tag); err != nil {
-			return fmt.Errorf("error decoding field %s: %w", fieldType.Name, err)
+			return wrapFieldError("decoding", fieldType.Name, err)
 		}
 	}
 
@@ -0,0 +1,69 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type eofRecord struct {
+	ID    uint32
+	Value uint32
+	Name  string
+}
+
+func encodeEOFRecords(t *testing.T, records ...eofRecord) []byte {
+	var stream bytes.Buffer
+	enc := NewEncoder(&stream)
+	for _, rec := range records {
+		assert.NoError(t, enc.Encode(rec))
+	}
+	return stream.Bytes()
+}
+
+func TestDecoderCleanEOF(t *testing.T) {
+	data := encodeEOFRecords(t, eofRecord{ID: 1, Name: "a"}, eofRecord{ID: 2, Name: "b"})
+
+	dec := NewDecoder(bytes.NewReader(data))
+	var rec eofRecord
+	assert.NoError(t, dec.Decode(&rec))
+	assert.NoError(t, dec.Decode(&rec))
+
+	// The end of the stream at a record boundary is exactly io.EOF
+	err := dec.Decode(&rec)
+	assert.Equal(t, io.EOF, err)
+
+	// Also for values whose first read is a fixed-size block
+	var ts time.Time
+	assert.Equal(t, io.EOF, NewDecoder(bytes.NewReader(nil)).Decode(&ts))
+}
+
+func TestDecoderTruncatedStream(t *testing.T) {
+	data := encodeEOFRecords(t, eofRecord{ID: 1, Value: 2, Name: "first"}, eofRecord{ID: 3, Value: 4, Name: "second"})
+	full := len(data) / 2
+
+	// Cut the second record at every possible position
+	for cut := full + 1; cut < len(data); cut++ {
+		dec := NewDecoder(bytes.NewReader(data[:cut]))
+		var rec eofRecord
+		assert.NoError(t, dec.Decode(&rec))
+
+		err := dec.Decode(&rec)
+		assert.Error(t, err)
+		assert.NotEqual(t, io.EOF, err, "cut at %d", cut)
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "cut at %d: %v", cut, err)
+	}
+}
+
+func TestDecoderTruncatedAtFieldBoundary(t *testing.T) {
+	// The stream ends exactly between the ID and Value fields
+	dec := NewDecoder(bytes.NewReader([]byte{1, 0, 0, 0}))
+	var rec eofRecord
+	err := dec.Decode(&rec)
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	assert.False(t, errors.Is(err, io.EOF))
+}
@@ -0,0 +1,78 @@
+package binary
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type resetRecord struct {
+	ID   uint32
+	Name string
+}
+
+func TestDecoderReset(t *testing.T) {
+	first, err := Marshal(resetRecord{ID: 1, Name: "first"})
+	assert.NoError(t, err)
+	second, err := Marshal(resetRecord{ID: 2, Name: "second"})
+	assert.NoError(t, err)
+
+	dec := NewDecoder(bytes.NewReader(nil))
+	dec.SetMaxAllocSize(5)
+
+	var decoded resetRecord
+	dec.Reset(first)
+	assert.NoError(t, dec.Decode(&decoded))
+	assert.Equal(t, resetRecord{ID: 1, Name: "first"}, decoded)
+
+	// Options survive a reset: "second" exceeds the limit of 5 bytes
+	dec.Reset(second)
+	err = dec.Decode(&decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds maximum allowed size")
+
+	// Unread input of the previous data is discarded
+	dec.SetMaxAllocSize(0)
+	dec.Reset(append(append([]byte{}, first...), first...))
+	assert.NoError(t, dec.Decode(&decoded))
+	dec.Reset(second)
+	assert.NoError(t, dec.Decode(&decoded))
+	assert.Equal(t, resetRecord{ID: 2, Name: "second"}, decoded)
+}
+
+func BenchmarkDecoderNewPerRecord(b *testing.B) {
+	data, err := Marshal(resetRecord{ID: 1, Name: "record"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	var decoded resetRecord
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 10000; j++ {
+			if err := NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkDecoderReset(b *testing.B) {
+	data, err := Marshal(resetRecord{ID: 1, Name: "record"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	var decoded resetRecord
+	dec := NewDecoder(nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 10000; j++ {
+			dec.Reset(data)
+			if err := dec.Decode(&decoded); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package binary
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoderThreeStructsFromPipe(t *testing.T) {
+	type Message struct {
+		Seq     uint32
+		Body    string
+		Payload []byte
+		Tags    []string `binary:"2"`
+	}
+
+	messages := []Message{
+		{Seq: 1, Body: "hello", Payload: []byte{1, 2, 3}, Tags: []string{"a", "b"}},
+		{Seq: 2, Body: "", Payload: []byte{}, Tags: []string{"", ""}},
+		{Seq: 3, Body: "a longer body", Payload: bytes.Repeat([]byte{7}, 64), Tags: []string{"x", ""}},
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		enc := NewEncoder(pw)
+		for _, m := range messages {
+			if err := enc.Encode(m); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.Close()
+	}()
+
+	dec := NewDecoder(pr)
+	for _, expected := range messages {
+		var decoded Message
+		assert.NoError(t, dec.Decode(&decoded))
+		assert.Equal(t, expected, decoded)
+	}
+
+	// Each Decode consumed exactly one value, so nothing is left on the stream
+	rest, err := io.ReadAll(pr)
+	assert.NoError(t, err)
+	assert.Empty(t, rest)
+}
+
+func TestDecoderLeavesReaderPositioned(t *testing.T) {
+	var stream bytes.Buffer
+	enc := NewEncoder(&stream)
+	assert.NoError(t, enc.Encode(uint16(0xBEEF)))
+	assert.NoError(t, enc.Encode("tail"))
+	stream.WriteString("unframed")
+
+	dec := NewDecoder(&stream)
+	var n uint16
+	assert.NoError(t, dec.Decode(&n))
+	assert.Equal(t, uint16(0xBEEF), n)
+
+	var s string
+	assert.NoError(t, dec.Decode(&s))
+	assert.Equal(t, "tail", s)
+	assert.Equal(t, "unframed", stream.String())
+}
+
+func TestDecoderErrors(t *testing.T) {
+	dec := NewDecoder(bytes.NewReader([]byte{1, 2, 3, 4}))
+
+	var notPointer uint32
+	assert.Error(t, dec.Decode(notPointer))
+
+	var nilPointer *uint32
+	assert.Error(t, dec.Decode(nilPointer))
+
+	// A BinaryUnmarshaler consumes the rest of the stream
+	var custom CustomType
+	dec = NewDecoder(bytes.NewReader([]byte("custom:value")))
+	assert.NoError(t, dec.Decode(&custom))
+	assert.Equal(t, "value", custom.Value)
+}
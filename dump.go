@@ -0,0 +1,53 @@
+package binary
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// dumpBytesPerLine is the number of bytes shown on each line of a DumpFormat hex dump
+const dumpBytesPerLine = 16
+
+// DumpFormat marshals v and returns an annotated hex dump of the result for debugging.
+// For a struct, each top-level field is shown on its own line with its byte offset,
+// name, type and length, followed by its bytes in hex. The annotations are recorded
+// while encoding, so they always match the output of Marshal. Other values are
+// shown as a single entry.
+func DumpFormat(v interface{}) (string, error) {
+	var data bytes.Buffer
+	layout := []FieldLayout{}
+	if err := encodeValue(v, &encodeState{w: &data, layout: &layout}); err != nil {
+		return "", err
+	}
+	if len(layout) == 0 && data.Len() > 0 {
+		layout = append(layout, FieldLayout{Name: "-", Type: fmt.Sprintf("%T", v), Length: data.Len()})
+	}
+
+	nameWidth, typeWidth := len("FIELD"), len("TYPE")
+	for _, field := range layout {
+		nameWidth = max(nameWidth, len(field.Name))
+		typeWidth = max(typeWidth, len(field.Type))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-6s  %-*s  %-*s  %6s  %s\n", "OFFSET", nameWidth, "FIELD", typeWidth, "TYPE", "LENGTH", "BYTES")
+	for _, field := range layout {
+		fieldData := data.Bytes()[field.Offset : field.Offset+field.Length]
+		fmt.Fprintf(&sb, "%06d  %-*s  %-*s  %6d ", field.Offset, nameWidth, field.Name, typeWidth, field.Type, field.Length)
+		if len(fieldData) == 0 {
+			sb.WriteString("\n")
+		}
+		// Long fields continue on indented lines
+		indent := strings.Repeat(" ", 6+2+nameWidth+2+typeWidth+2+6+1)
+		for start := 0; start < len(fieldData); start += dumpBytesPerLine {
+			if start > 0 {
+				sb.WriteString(indent)
+			}
+			end := min(start+dumpBytesPerLine, len(fieldData))
+			fmt.Fprintf(&sb, " % x\n", fieldData[start:end])
+		}
+	}
+	fmt.Fprintf(&sb, "total %d bytes\n", data.Len())
+	return sb.String(), nil
+}
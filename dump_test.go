@@ -0,0 +1,89 @@
+package binary
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpFormat(t *testing.T) {
+	dump, err := DumpFormat(examplePerson())
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(dump, "\n"), "\n")
+	assert.True(t, strings.HasPrefix(lines[0], "OFFSET  FIELD"))
+	assert.Equal(t, "total 150 bytes", lines[len(lines)-1])
+
+	// Each field starts a line with its offset, name, type and length
+	expected := []struct {
+		offset int
+		name   string
+		typ    string
+		length int
+	}{
+		{0, "Name", "string", 9},
+		{9, "Age", "uint8", 1},
+		{10, "Email", "string", 50},
+		{60, "Data", "[]uint8", 10},
+		{70, "Scores", "[]uint32", 20},
+		{90, "ID", "[16]uint8", 16},
+		{106, "Values", "[4]uint32", 16},
+		{122, "Address", "string", 15},
+		{137, "Height", "float32", 4},
+		{141, "Weight", "float64", 8},
+		{149, "HaveChild", "bool", 1},
+	}
+	var fieldLines [][]string
+	for _, line := range lines[1 : len(lines)-1] {
+		if !strings.HasPrefix(line, " ") {
+			fieldLines = append(fieldLines, strings.Fields(line))
+		}
+	}
+	assert.Len(t, fieldLines, len(expected))
+	for i, want := range expected {
+		assert.Equal(t, []string{fmt.Sprintf("%06d", want.offset), want.name, want.typ, fmt.Sprint(want.length)}, fieldLines[i][:4])
+	}
+
+	assert.Contains(t, dump, "05 00 00 00 41 6c 69 63 65\n")
+
+	// Long fields wrap onto continuation lines aligned with the first line of bytes
+	assert.True(t, strings.HasPrefix(lines[3], "000010  Email"))
+	assert.Equal(t, strings.Index(lines[3], "61 6c"), strings.Index(lines[4], "6d 00"))
+}
+
+func TestDumpFormatNonStruct(t *testing.T) {
+	dump, err := DumpFormat(uint16(0x0102))
+	assert.NoError(t, err)
+	assert.Contains(t, dump, "000000  -")
+	assert.Contains(t, dump, "uint16")
+	assert.Contains(t, dump, "02 01\n")
+
+	_, err = DumpFormat(make(chan int))
+	assert.Error(t, err)
+}
+
+func TestDumpFormatContainersOfStructs(t *testing.T) {
+	type Point struct {
+		A uint8
+		B string
+	}
+
+	// The elements of a slice or map are not top-level fields, so the value
+	// is shown as a single entry including its length prefix
+	for _, v := range []interface{}{
+		[]Point{{1, "x"}, {2, "y"}},
+		map[string]Point{"p": {1, "x"}},
+	} {
+		data, err := Marshal(v)
+		assert.NoError(t, err)
+
+		dump, err := DumpFormat(v)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSuffix(dump, "\n"), "\n")
+		assert.Len(t, lines, 3)
+		assert.Equal(t, []string{"000000", "-", fmt.Sprintf("%T", v), fmt.Sprint(len(data))}, strings.Fields(lines[1])[:4])
+		assert.Equal(t, fmt.Sprintf("total %d bytes", len(data)), lines[2])
+	}
+}
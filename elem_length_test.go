@@ -0,0 +1,103 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedCountFixedWidthStrings(t *testing.T) {
+	type Header struct {
+		Names [3]string `binary:"elem:8"`
+		Tags  []string  `binary:"count:4,elem:16"`
+		After uint8
+	}
+
+	original := Header{
+		Names: [3]string{"alpha", "", "gamma"},
+		Tags:  []string{"red", "green"},
+		After: 9,
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// No prefixes at all: 3*8 bytes of names, 4*16 bytes of tags, then After
+	assert.Len(t, data, 3*8+4*16+1)
+	assert.Equal(t, []byte("alpha\x00\x00\x00"), data[:8])
+	assert.Equal(t, []byte("red"), data[24:27])
+	assert.Equal(t, byte(9), data[len(data)-1])
+
+	size, err := Size(original)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), size)
+
+	var decoded Header
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original.Names, decoded.Names)
+	// The slice has exactly count elements, padded with empty strings
+	assert.Equal(t, []string{"red", "green", "", ""}, decoded.Tags)
+	assert.Equal(t, uint8(9), decoded.After)
+}
+
+func TestFixedWidthElementsTruncate(t *testing.T) {
+	type Names struct {
+		Values []string `binary:"count:2,elem:4"`
+	}
+
+	data, err := Marshal(Names{Values: []string{"abcdef", "xy", "dropped"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{'a', 'b', 'c', 'd', 'x', 'y', 0, 0}, data)
+
+	var decoded Names
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, []string{"abcd", "xy"}, decoded.Values)
+}
+
+func TestFixedWidthElementsWithPrefix(t *testing.T) {
+	type Record struct {
+		Codes  []string          `binary:"elem:3,prefix:1"`
+		Chunks [][]byte          `binary:"elem:2"`
+		Labels map[string]string `binary:"elem:4"`
+	}
+
+	original := Record{
+		Codes:  []string{"abc", "de"},
+		Chunks: [][]byte{{1, 2}, {3, 4}},
+		Labels: map[string]string{"k": "v"},
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// The element count is still prefixed, but each element is fixed width
+	assert.Equal(t, []byte{2, 'a', 'b', 'c', 'd', 'e', 0}, data[:7])
+	assert.Len(t, data, 7+4+2*2+4+2*4)
+
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestElemTagInvalid(t *testing.T) {
+	_, err := parseTag("count:2,elem:x")
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
+
+func TestFixedCountKeepsElementOptions(t *testing.T) {
+	type Record struct {
+		Values []int64    `binary:"count:3,varint"`
+		Names  []string   `binary:"count:2,prefix:1"`
+		Ratios [2]float64 `binary:"2,f32"`
+	}
+
+	original := Record{Values: []int64{1, -1, 300}, Names: []string{"ab", "c"}, Ratios: [2]float64{0.5, 2}}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	want := []byte{2, 1, 0xd8, 0x04, 2, 'a', 'b', 1, 'c', 0, 0, 0, 0x3f, 0, 0, 0, 0x40}
+	assert.Equal(t, want, data)
+
+	size, err := Size(original)
+	assert.NoError(t, err)
+	assert.Equal(t, len(want), size)
+
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
@@ -0,0 +1,70 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Base struct {
+	ID uint32
+}
+
+type User struct {
+	Base
+	Name string
+}
+
+type auditInfo struct {
+	Revision uint16
+}
+
+type Account struct {
+	auditInfo
+	Base
+	Owner string `binary:"8"`
+}
+
+func TestEmbeddedStructFlattened(t *testing.T) {
+	user := User{Base: Base{ID: 7}, Name: "bob"}
+
+	data, err := Marshal(user)
+	assert.NoError(t, err)
+
+	// ID is written inline, followed by the length-prefixed name
+	assert.Equal(t, []byte{7, 0, 0, 0, 3, 0, 0, 0, 'b', 'o', 'b'}, data)
+
+	var decoded User
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, user, decoded)
+
+	// Promoted fields are reported at the parent level
+	layout, err := Layout(user)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldLayout{
+		{Name: "ID", Type: "uint32", Offset: 0, Length: 4},
+		{Name: "Name", Type: "string", Offset: 4, Length: 7},
+	}, layout)
+}
+
+func TestEmbeddedStructUnexportedSkipped(t *testing.T) {
+	account := Account{auditInfo: auditInfo{Revision: 3}, Base: Base{ID: 1}, Owner: "alice"}
+
+	data, err := Marshal(account)
+	assert.NoError(t, err)
+	assert.Len(t, data, 12)
+
+	var decoded Account
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, uint16(0), decoded.Revision)
+	assert.Equal(t, account.Base, decoded.Base)
+	assert.Equal(t, account.Owner, decoded.Owner)
+}
+
+func TestEmbeddedStructErrorNamesField(t *testing.T) {
+	var decoded User
+	err := Unmarshal([]byte{1, 0}, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "field ID")
+	assert.NotContains(t, err.Error(), "field Base")
+}
@@ -2,36 +2,183 @@ package binary
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"encoding/binary"
 	"fmt"
+	"io"
+	"math/big"
+	"net"
 	"reflect"
+	"slices"
+	"strings"
+	"time"
 )
 
 // Marshal serializes a value into binary format
 func Marshal(v interface{}) ([]byte, error) {
+	return defaultCodec.Marshal(v)
+}
+
+// encodeValue serializes a top-level value
+func encodeValue(v interface{}, buf *encodeState) error {
 	// Check if the value implements BinaryMarshaler
-	if marshaler, ok := v.(BinaryMarshaler); ok {
-		return marshaler.MarshalBinary()
+	if marshaler, ok := asMarshaler(v); ok {
+		data, err := marshaler.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		_, err = buf.Write(data)
+		return err
 	}
 
 	val := reflect.ValueOf(v)
 
+	// Top-level pointers are dereferenced without a presence byte, so Marshal(&v) equals Marshal(v)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return fmt.Errorf("cannot encode %w", ErrNilPointer)
+		}
+		if err := buf.enterPointer(val); err != nil {
+			return fmt.Errorf("error marshaling value: %w", err)
+		}
+		val = val.Elem()
+	}
+
+	// A top-level Raw value is written verbatim
+	if val.IsValid() && val.Type() == rawType {
+		return encodeRest(val, buf)
+	}
+
 	// Marshal any type by calling encodeField directly
-	var buf bytes.Buffer
 	tag := "" // No tag for direct encoding
-	if err := encodeField(val, &buf, tag); err != nil {
-		return nil, fmt.Errorf("error marshaling value: %w", err)
+	if err := encodeField(val, buf, tag); err != nil {
+		return fmt.Errorf("error marshaling value: %w", err)
 	}
 
-	return buf.Bytes(), nil
+	return nil
+}
+
+// encodeState carries the output and encoding options through a single encoding pass
+type encodeState struct {
+	codecOptions
+	w     io.Writer
+	n     int64 // number of bytes written so far
+	depth int   // nesting depth of the struct being encoded
+	// number of enclosing slices, arrays, maps and interfaces
+	containers int
+	layout     *[]FieldLayout  // if set, records the layout of top-level struct fields
+	group      string          // if set, only fields without a group or in this group are encoded
+	ctx        context.Context // if set, slices and maps stop encoding once it is canceled
+
+	// pointers currently being encoded, used to detect cycles
+	visiting map[visitedPointer]struct{}
+}
+
+// visitedPointer identifies a pointer being encoded. The type is part of the key
+// because a struct and its first field share the same address.
+type visitedPointer struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// enterPointer marks a non-nil pointer as being encoded. It fails if the pointer
+// is already being encoded further up, which means the value contains a cycle
+// that would otherwise recurse forever.
+func (e *encodeState) enterPointer(p reflect.Value) error {
+	key := visitedPointer{addr: p.Pointer(), typ: p.Type()}
+	if _, ok := e.visiting[key]; ok {
+		return fmt.Errorf("cycle detected at %s", p.Type())
+	}
+	if e.visiting == nil {
+		e.visiting = make(map[visitedPointer]struct{})
+	}
+	e.visiting[key] = struct{}{}
+	return nil
+}
+
+// leavePointer unmarks a pointer once its value has been encoded, so the same
+// pointer may appear again elsewhere in the value as long as it is not nested in itself
+func (e *encodeState) leavePointer(p reflect.Value) {
+	delete(e.visiting, visitedPointer{addr: p.Pointer(), typ: p.Type()})
+}
+
+// Write writes p to the underlying writer and keeps track of the output offset
+func (e *encodeState) Write(p []byte) (int, error) {
+	n, err := e.w.Write(p)
+	e.n += int64(n)
+	return n, err
+}
+
+// MarshalAppend appends the binary encoding of v to dst and returns the extended slice.
+// Like append, it only allocates when dst does not have enough spare capacity,
+// so reusing the returned slice across calls avoids repeated allocations.
+// On error, dst is returned with its original length.
+func MarshalAppend(dst []byte, v interface{}) ([]byte, error) {
+	return defaultCodec.AppendTo(dst, v)
+}
+
+// MarshalTo serializes a value and writes it to w.
+// It returns the number of bytes written, including all length prefixes.
+// Nothing is written if the value cannot be marshaled.
+// A *bytes.Buffer, e.g. one taken from a pool, is appended to directly
+// without building the encoding in an intermediate buffer first.
+func MarshalTo(w io.Writer, v interface{}) (int64, error) {
+	if buf, ok := w.(*bytes.Buffer); ok {
+		start := buf.Len()
+		state := &encodeState{w: buf}
+		if err := encodeValue(v, state); err != nil {
+			buf.Truncate(start)
+			return 0, err
+		}
+		return state.n, nil
+	}
+
+	data, err := Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// Size returns the number of bytes that Marshal would produce for v.
+// It runs the regular encoding logic but only counts the output, so the
+// result accounts for fixed-length tags, length prefixes and nested values.
+func Size(v interface{}) (int, error) {
+	return defaultCodec.Size(v)
 }
 
 // encodeStruct handles serialization of a struct
-func encodeStruct(val reflect.Value, buf *bytes.Buffer) error {
+func encodeStruct(val reflect.Value, buf *encodeState) error {
+	val, err := callPreMarshal(val)
+	if err != nil {
+		return err
+	}
+
+	recordLayout := buf.layout != nil && buf.depth == 0 && buf.containers == 0
+	trailing := buf.depth == 0 && buf.containers == 0
+	buf.depth++
+	defer func() { buf.depth-- }()
+
+	return encodeStructFields(val, buf, recordLayout, trailing)
+}
+
+// encodeStructFields writes the fields of a struct in declaration order.
+// Embedded structs are flattened, so their fields appear inline at the parent level.
+// trailing reports whether the struct ends the top-level value, which is the
+// only place a "rest" or Raw field may appear.
+func encodeStructFields(val reflect.Value, buf *encodeState, recordLayout, trailing bool) error {
 	typ := val.Type()
-	numField := val.NumField()
+	var bits bitWriter
 
-	for i := 0; i < numField; i++ {
+	// Fields holding the length of a later "lenfrom" field are written from that field
+	lengths, err := lenFromLengths(val)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range fieldOrder(typ) {
 		field := val.Field(i)
 		fieldType := typ.Field(i)
 
@@ -40,64 +187,215 @@ func encodeStruct(val reflect.Value, buf *bytes.Buffer) error {
 			continue
 		}
 
-		// Check if field implements BinaryMarshaler
-		if marshaler, ok := field.Interface().(BinaryMarshaler); ok {
+		included, tag := inGroup(fieldType.Tag.Get("binary"), buf.group)
+		if !included {
+			continue
+		}
+		if tag, err = withoutOrderOption(tag); err != nil {
+			return wrapFieldError("encoding", fieldType.Name, err)
+		}
+
+		// Consecutive bool fields tagged "bits" share bytes
+		if tag == bitsTag {
+			if recordLayout {
+				*buf.layout = append(*buf.layout, FieldLayout{Name: fieldType.Name, Type: fieldType.Type.String(), Offset: int(buf.n), Length: 1})
+			}
+			if err := bits.writeBit(buf, field); err != nil {
+				return wrapFieldError("encoding", fieldType.Name, err)
+			}
+			continue
+		}
+		if err := bits.flush(buf); err != nil {
+			return err
+		}
+
+		// If tag is "-", skip this field entirely, even if it has its own marshaler
+		if tag == "-" {
+			continue
+		}
+
+		offset := buf.n
+
+		// Pointers to custom marshalers keep their presence byte, so that nil
+		// fields round-trip
+		if field.Kind() == reflect.Ptr && usesCustomCodec(field.Type().Elem()) {
+			if err := encodeCustomPointer(field, buf); err != nil {
+				return wrapFieldError("marshaling", fieldType.Name, err)
+			}
+			if recordLayout {
+				buf.recordField(fieldType, offset)
+			}
+			continue
+		}
+
+		// Check if field implements BinaryMarshaler. Interface fields are
+		// encoded with their type code instead, see encodeInterface.
+		if marshaler, ok := fieldMarshaler(field); ok && field.Kind() != reflect.Interface {
 			fieldData, err := marshaler.MarshalBinary()
 			if err != nil {
-				return fmt.Errorf("error marshaling field %s: %w", fieldType.Name, err)
+				return wrapFieldError("marshaling", fieldType.Name, err)
 			}
 			// Write length + data for the field
 			length := uint32(len(fieldData))
-			if err := binary.Write(buf, binary.LittleEndian, length); err != nil {
+			if err := binary.Write(buf, buf.byteOrder(), length); err != nil {
 				return err
 			}
 			_, err = buf.Write(fieldData)
 			if err != nil {
 				return err
 			}
+			if recordLayout {
+				buf.recordField(fieldType, offset)
+			}
 			continue
 		}
 
-		tag := fieldType.Tag.Get("binary")
-		// If tag is "-", skip this field entirely
-		if tag == "-" {
+		// A trailing "rest" or Raw field is written without a length prefix
+		if isRestField(fieldType, tag) {
+			if err := checkRestField(val, i, trailing, buf.group); err != nil {
+				return wrapFieldError("encoding", fieldType.Name, err)
+			}
+			if err := encodeRest(field, buf); err != nil {
+				return wrapFieldError("encoding", fieldType.Name, err)
+			}
+			if recordLayout {
+				buf.recordField(fieldType, offset)
+			}
 			continue
 		}
 
+		if isEmbeddedStruct(fieldType) {
+			if err := encodeStructFields(field, buf, recordLayout, trailing && isLastField(val.Type(), i, buf.group)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Fields tagged "omitempty" are written as a presence byte, followed by the value if it is not zero
+		if hasTagOption(tag, "omitempty") {
+			tag = withoutTagOption(tag, "omitempty")
+			if field.IsZero() {
+				if _, err := buf.Write([]byte{0}); err != nil {
+					return err
+				}
+				if recordLayout {
+					buf.recordField(fieldType, offset)
+				}
+				continue
+			}
+			if _, err := buf.Write([]byte{1}); err != nil {
+				return err
+			}
+		}
+
+		if length, ok := lengths[i]; ok {
+			if field, err = lengthValue(field.Type(), length); err != nil {
+				return wrapFieldError("encoding", fieldType.Name, err)
+			}
+		}
+		if _, ok := lenFromOption(tag); ok {
+			tag = withFixedLength(tag, field.Len())
+		}
+
 		if err := encodeField(field, buf, tag); err != nil {
-			return fmt.Errorf("error encoding field %s: %w", fieldType.Name, err)
+			return wrapFieldError("encoding", fieldType.Name, err)
+		}
+		if recordLayout {
+			buf.recordField(fieldType, offset)
 		}
 	}
 
-	return nil
+	return bits.flush(buf)
 }
 
 // encodeField handles serialization of a single field
-func encodeField(field reflect.Value, buf *bytes.Buffer, tag string) error {
+func encodeField(field reflect.Value, buf *encodeState, tag string) error {
 	// If tag is "-", skip this field entirely (consistent with struct behavior)
 	if tag == "-" {
 		return nil
 	}
 
+	if hasTagOption(tag, "varint") && isIntegerKind(field.Kind()) {
+		return encodeVarint(field, buf)
+	}
+
 	switch field.Kind() {
 	case reflect.Ptr:
-		// Handle pointer types by dereferencing them
+		// Pointers are written as a presence byte (0 = nil, 1 = present) followed by the value
 		if field.IsNil() {
-			return fmt.Errorf("cannot encode nil pointer")
+			_, err := buf.Write([]byte{0})
+			return err
+		}
+		if err := buf.enterPointer(field); err != nil {
+			return err
+		}
+		defer buf.leavePointer(field)
+		if _, err := buf.Write([]byte{1}); err != nil {
+			return err
 		}
 		return encodeField(field.Elem(), buf, tag)
 
 	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
-		reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int, reflect.Bool:
-		return binary.Write(buf, binary.LittleEndian, field.Interface())
+		reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Bool:
+		return binary.Write(buf, buf.byteOrder(), field.Interface())
+
+	case reflect.Int:
+		// int is always encoded as 8 bytes so data is portable across architectures
+		return binary.Write(buf, buf.byteOrder(), field.Int())
+
+	case reflect.Uint, reflect.Uintptr:
+		// uint and uintptr are always encoded as 8 bytes so data is portable across architectures
+		return binary.Write(buf, buf.byteOrder(), field.Uint())
 
 	case reflect.Float32, reflect.Float64:
-		return binary.Write(buf, binary.LittleEndian, field.Interface())
+		if info, err := parseTag(tag); err == nil {
+			if info.HasScale {
+				return encodeScaledFloat(field.Float(), buf, info.Scale)
+			}
+			if info.Decimal {
+				return encodeDecimalFloat(field.Float(), field.Type().Bits(), buf, tag)
+			}
+			if info.Float32 && field.Kind() == reflect.Float64 {
+				// Explicitly trade precision for space by narrowing to float32
+				return binary.Write(buf, buf.byteOrder(), float32(field.Float()))
+			}
+		}
+		return binary.Write(buf, buf.byteOrder(), field.Interface())
+
+	case reflect.Complex64, reflect.Complex128:
+		// Complex numbers are written as the real part followed by the imaginary part,
+		// each as a float of half the complex width
+		return binary.Write(buf, buf.byteOrder(), field.Interface())
 
 	case reflect.String:
+		if info, err := parseTag(tag); err == nil && info.HasScale {
+			return encodeDecimalString(field.String(), buf, info.Scale)
+		} else if err == nil && info.UTF16 {
+			return encodeUTF16String(field.String(), buf, tag)
+		}
 		return encodeString(field.String(), buf, tag)
 
 	case reflect.Slice:
+		if field.Type() == rawType {
+			if err := checkRawTag(tag); err != nil {
+				return err
+			}
+		}
+		if hasNilPresence(buf.codecOptions, field.Type()) {
+			if isNil, err := encodeNilPresence(field, buf); isNil || err != nil {
+				return err
+			}
+		}
+		if hasTagOption(tag, packedTag) {
+			return encodePackedBools(field, buf, tag)
+		}
+		// The runes of a [][]rune are handled element by element
+		if hasTagOption(tag, runesTag) && field.Type().Elem().Kind() != reflect.Slice {
+			return encodeRunes(field, buf, tag)
+		}
+		if field.Type() == ipType {
+			return encodeIP(field.Interface().(net.IP), buf)
+		}
 		if field.Type().Elem().Kind() == reflect.Uint8 {
 			// []byte
 			return encodeBytes(field.Bytes(), buf, tag)
@@ -118,21 +416,61 @@ func encodeField(field reflect.Value, buf *bytes.Buffer, tag string) error {
 		// Other arrays
 		return encodeArray(field, buf, tag)
 
+	case reflect.Map:
+		if hasNilPresence(buf.codecOptions, field.Type()) {
+			if isNil, err := encodeNilPresence(field, buf); isNil || err != nil {
+				return err
+			}
+		}
+		return encodeMap(field, buf, tag)
+
 	case reflect.Struct:
+		if field.Type() == timeType {
+			return encodeTime(field.Interface().(time.Time), buf)
+		}
+		if field.Type() == ipNetType {
+			return encodeIPNet(field.Interface().(net.IPNet), buf)
+		}
+		if field.Type() == bigIntType {
+			x := field.Interface().(big.Int)
+			return encodeBigInt(&x, buf, tag)
+		}
+		if field.Type() == bigRatType {
+			x := field.Interface().(big.Rat)
+			return encodeBigRat(&x, buf, tag)
+		}
+		// Structs with custom marshalers inside slices, arrays and maps are
+		// written as length + data, like struct fields
+		if usesCustomCodec(field.Type()) {
+			return encodeCustom(field, buf)
+		}
 		return encodeStruct(field, buf)
 
+	case reflect.Interface:
+		return encodeInterface(field, buf, tag)
+
 	default:
-		return fmt.Errorf("unsupported type: %s", field.Kind())
+		return &UnsupportedTypeError{Kind: field.Kind()}
 	}
 }
 
 // encodeString handles serialization of strings
-func encodeString(s string, buf *bytes.Buffer, tag string) error {
+func encodeString(s string, buf *encodeState, tag string) error {
 	data := []byte(s)
 
 	// Check if tag specifies length
 	if tag != "" {
-		if length, err := parseTag(tag); err == nil {
+		if info, err := parseTag(tag); err == nil && info.HasFixedLen {
+			length := info.FixedLen
+			if info.CString {
+				// C strings always need room for the NUL terminator, and cannot contain NUL themselves
+				if uint32(len(data)) >= length {
+					return fmt.Errorf("string of %d bytes does not fit in a %d-byte C string", len(data), length)
+				}
+				if bytes.IndexByte(data, 0) >= 0 {
+					return fmt.Errorf("C string contains a NUL byte")
+				}
+			}
 			if length == 0 {
 				// For zero-length strings, write nothing
 				return nil
@@ -153,8 +491,7 @@ func encodeString(s string, buf *bytes.Buffer, tag string) error {
 	}
 
 	// Default format: len(data) + data
-	length := uint32(len(data))
-	if err := binary.Write(buf, binary.LittleEndian, length); err != nil {
+	if err := writeLength(buf, len(data), tag); err != nil {
 		return err
 	}
 	_, err := buf.Write(data)
@@ -162,10 +499,11 @@ func encodeString(s string, buf *bytes.Buffer, tag string) error {
 }
 
 // encodeBytes handles serialization of []byte and [N]byte
-func encodeBytes(b []byte, buf *bytes.Buffer, tag string) error {
+func encodeBytes(b []byte, buf *encodeState, tag string) error {
 	// Check if tag specifies length
 	if tag != "" {
-		if length, err := parseTag(tag); err == nil {
+		if info, err := parseTag(tag); err == nil && info.HasFixedLen {
+			length := info.FixedLen
 			if length == 0 {
 				// For zero-length bytes, write nothing
 				return nil
@@ -186,24 +524,63 @@ func encodeBytes(b []byte, buf *bytes.Buffer, tag string) error {
 	}
 
 	// Default format: len(data) + data
-	length := uint32(len(b))
-	if err := binary.Write(buf, binary.LittleEndian, length); err != nil {
+	if err := writeLength(buf, len(b), tag); err != nil {
 		return err
 	}
 	_, err := buf.Write(b)
 	return err
 }
 
+// writeLength writes the length prefix of a variable-length field using the
+// width and byte order selected by the tag
+func writeLength(buf *encodeState, length int, tag string) error {
+	info, err := parseTag(tag)
+	if err != nil {
+		return err
+	}
+	prefix := info.prefix(buf.codecOptions)
+	if prefix.varint {
+		var data [binary.MaxVarintLen64]byte
+		n := binary.PutUvarint(data[:], uint64(length))
+		_, err = buf.Write(data[:n])
+		return err
+	}
+	if prefix.width < 8 && uint64(length) >= 1<<(8*prefix.width) {
+		return fmt.Errorf("length %d does not fit in a %d-byte length prefix", length, prefix.width)
+	}
+
+	var data [8]byte
+	switch prefix.width {
+	case 1:
+		data[0] = uint8(length)
+	case 2:
+		prefix.order.PutUint16(data[:], uint16(length))
+	case 4:
+		prefix.order.PutUint32(data[:], uint32(length))
+	case 8:
+		prefix.order.PutUint64(data[:], uint64(length))
+	}
+	_, err = buf.Write(data[:prefix.width])
+	return err
+}
+
 // encodeSlice handles serialization of slices (except []byte)
-func encodeSlice(slice reflect.Value, buf *bytes.Buffer, tag string) error {
+func encodeSlice(slice reflect.Value, buf *encodeState, tag string) error {
+	buf.containers++
+	defer func() { buf.containers-- }()
+
 	// Check if tag specifies length
 	if tag != "" {
-		if length, err := parseTag(tag); err == nil {
+		if info, err := parseTag(tag); err == nil && info.HasFixedLen {
+			length := info.FixedLen
 			// For fixed-length slices, we don't write the length prefix
 			sliceLen := uint32(slice.Len())
 			elemType := slice.Type().Elem()
 
 			for i := uint32(0); i < length; i++ {
+				if err := checkCanceled(buf.ctx, int(i)); err != nil {
+					return err
+				}
 				var elem reflect.Value
 				if i < sliceLen {
 					elem = slice.Index(int(i))
@@ -211,8 +588,8 @@ func encodeSlice(slice reflect.Value, buf *bytes.Buffer, tag string) error {
 					elem = reflect.Zero(elemType)
 				}
 
-				if err := encodeField(elem, buf, ""); err != nil {
-					return err
+				if err := encodeField(elem, buf, elementTag(tag)); err != nil {
+					return wrapFieldError("encoding", indexSegment(i), err)
 				}
 			}
 			return nil
@@ -220,27 +597,38 @@ func encodeSlice(slice reflect.Value, buf *bytes.Buffer, tag string) error {
 	}
 
 	// Default format: len(slice) + elements
-	length := uint32(slice.Len())
-	if err := binary.Write(buf, binary.LittleEndian, length); err != nil {
+	length := slice.Len()
+	if err := writeLength(buf, length, tag); err != nil {
 		return err
 	}
 
+	if isBulkSlice(slice.Type(), elementTag(tag)) {
+		return encodeBulkSlice(slice, buf)
+	}
+
 	// Write each element
-	for i := 0; i < int(length); i++ {
-		elem := slice.Index(i)
-		if err := encodeField(elem, buf, ""); err != nil {
+	for i := 0; i < length; i++ {
+		if err := checkCanceled(buf.ctx, i); err != nil {
 			return err
 		}
+		elem := slice.Index(i)
+		if err := encodeField(elem, buf, elementTag(tag)); err != nil {
+			return wrapFieldError("encoding", indexSegment(i), err)
+		}
 	}
 
 	return nil
 }
 
 // encodeArray handles serialization of arrays (except [N]byte)
-func encodeArray(array reflect.Value, buf *bytes.Buffer, tag string) error {
+func encodeArray(array reflect.Value, buf *encodeState, tag string) error {
+	buf.containers++
+	defer func() { buf.containers-- }()
+
 	// Check if tag specifies length
 	if tag != "" {
-		if length, err := parseTag(tag); err == nil {
+		if info, err := parseTag(tag); err == nil && info.HasFixedLen {
+			length := info.FixedLen
 			// For fixed-length arrays, we don't write the length prefix
 			arrayLen := uint32(array.Len())
 			elemType := array.Type().Elem()
@@ -253,8 +641,8 @@ func encodeArray(array reflect.Value, buf *bytes.Buffer, tag string) error {
 					elem = reflect.Zero(elemType)
 				}
 
-				if err := encodeField(elem, buf, ""); err != nil {
-					return err
+				if err := encodeField(elem, buf, elementTag(tag)); err != nil {
+					return wrapFieldError("encoding", indexSegment(i), err)
 				}
 			}
 			return nil
@@ -263,14 +651,99 @@ func encodeArray(array reflect.Value, buf *bytes.Buffer, tag string) error {
 
 	// For arrays without tags, we also don't write the length prefix
 	// because the length is fixed and known at compile time
+	if isBulkArray(array.Type(), elementTag(tag)) {
+		// Multi-dimensional arrays are written in row-major order, like nested loops would
+		return binary.Write(buf, buf.byteOrder(), array.Interface())
+	}
 	length := uint32(array.Len())
 
 	for i := uint32(0); i < length; i++ {
 		elem := array.Index(int(i))
-		if err := encodeField(elem, buf, ""); err != nil {
+		if err := encodeField(elem, buf, elementTag(tag)); err != nil {
+			return wrapFieldError("encoding", indexSegment(i), err)
+		}
+	}
+
+	return nil
+}
+
+// encodeMap handles serialization of maps as len(map) + key/value pairs.
+// Keys are written in sorted order so that the output is deterministic.
+func encodeMap(m reflect.Value, buf *encodeState, tag string) error {
+	buf.containers++
+	defer func() { buf.containers-- }()
+
+	if err := writeLength(buf, m.Len(), tag); err != nil {
+		return err
+	}
+
+	keys, err := sortedMapKeys(m, buf, tag)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		if err := checkCanceled(buf.ctx, i); err != nil {
 			return err
 		}
+		if err := encodeField(key, buf, elementTag(tag)); err != nil {
+			return wrapFieldError("encoding", indexSegment(key), err)
+		}
+		if err := encodeField(m.MapIndex(key), buf, elementTag(tag)); err != nil {
+			return wrapFieldError("encoding", indexSegment(key), err)
+		}
 	}
 
 	return nil
 }
+
+// sortedMapKeys returns the keys of a map in a deterministic order.
+// Strings, integers, floats and bools are sorted by value; keys of other
+// types are sorted by their encoded bytes.
+func sortedMapKeys(m reflect.Value, buf *encodeState, tag string) ([]reflect.Value, error) {
+	keys := m.MapKeys()
+
+	var compare func(a, b reflect.Value) int
+	switch m.Type().Key().Kind() {
+	case reflect.String:
+		compare = func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) }
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.Int(), b.Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.Uint(), b.Uint()) }
+	case reflect.Float32, reflect.Float64:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.Float(), b.Float()) }
+	case reflect.Bool:
+		compare = func(a, b reflect.Value) int {
+			if a.Bool() == b.Bool() {
+				return 0
+			}
+			if b.Bool() {
+				return -1
+			}
+			return 1
+		}
+	default:
+		encoded := make([][]byte, len(keys))
+		for i, key := range keys {
+			var keyBuf bytes.Buffer
+			// Keys are encoded with the codec's options, so the order follows the bytes written
+			if err := encodeField(key, &encodeState{w: &keyBuf, codecOptions: buf.codecOptions}, elementTag(tag)); err != nil {
+				return nil, err
+			}
+			encoded[i] = keyBuf.Bytes()
+		}
+		order := make([]int, len(keys))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortFunc(order, func(a, b int) int { return bytes.Compare(encoded[a], encoded[b]) })
+		sorted := make([]reflect.Value, len(keys))
+		for i, index := range order {
+			sorted[i] = keys[index]
+		}
+		return sorted, nil
+	}
+
+	slices.SortFunc(keys, compare)
+	return keys, nil
+}
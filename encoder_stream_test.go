@@ -0,0 +1,73 @@
+package binary
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoderMatchesMarshal(t *testing.T) {
+	type Record struct {
+		ID    uint32
+		Name  string
+		Tags  []string
+		Code  string `binary:"6"`
+		Extra CustomType
+	}
+
+	records := []interface{}{
+		Record{ID: 1, Name: "first", Tags: []string{"a"}, Code: "x", Extra: CustomType{Value: "e"}},
+		Record{ID: 2, Name: "second", Code: "yz"},
+		[]uint16{1, 2, 3},
+		CustomType{Value: "top-level"},
+	}
+
+	var stream bytes.Buffer
+	var expected []byte
+	enc := NewEncoder(&stream)
+	for _, r := range records {
+		assert.NoError(t, enc.Encode(r))
+		data, err := Marshal(r)
+		assert.NoError(t, err)
+		expected = append(expected, data...)
+	}
+
+	// Consecutive values are appended with the same framing as Marshal
+	assert.Equal(t, expected, stream.Bytes())
+}
+
+func TestEncoderErrors(t *testing.T) {
+	var stream bytes.Buffer
+	enc := NewEncoder(&stream)
+	assert.Error(t, enc.Encode(make(chan int)))
+
+	w := &shortWriter{limit: 2}
+	assert.Error(t, NewEncoder(w).Encode(uint32(1)))
+}
+
+func TestEncoderDecoderIntAndBool(t *testing.T) {
+	type Flags struct {
+		Enabled bool
+		Counts  []int
+		Offset  int
+	}
+
+	original := Flags{Enabled: true, Counts: []int{-1, 0, 1 << 40}, Offset: -7}
+	var stream bytes.Buffer
+	enc := NewEncoder(&stream)
+	assert.NoError(t, enc.Encode(original))
+	assert.NoError(t, enc.Encode(true))
+	assert.NoError(t, enc.Encode([]int{3}))
+
+	dec := NewDecoder(&stream)
+	var flags Flags
+	assert.NoError(t, dec.Decode(&flags))
+	assert.Equal(t, original, flags)
+	var b bool
+	assert.NoError(t, dec.Decode(&b))
+	assert.True(t, b)
+	var ints []int
+	assert.NoError(t, dec.Decode(&ints))
+	assert.Equal(t, []int{3}, ints)
+}
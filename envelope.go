@@ -0,0 +1,32 @@
+package binary
+
+import "fmt"
+
+// Envelope is a standard message header that frames a marshaled payload with
+// a version and a type identifier. Its wire format is the regular encoding of
+// the struct: Version (2 bytes), Type (4 bytes), then the length-prefixed Payload.
+type Envelope struct {
+	Version uint16
+	Type    uint32
+	Payload []byte
+}
+
+// MarshalEnvelope marshals v and wraps the result in an Envelope with the given version and type identifier
+func MarshalEnvelope(version uint16, typeID uint32, v interface{}) ([]byte, error) {
+	payload, err := Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling envelope payload: %w", err)
+	}
+	return Marshal(Envelope{Version: version, Type: typeID, Payload: payload})
+}
+
+// UnmarshalEnvelope parses an Envelope and returns its header fields and the
+// still-encoded payload, leaving it to the caller to decode the payload based
+// on the version and type identifier
+func UnmarshalEnvelope(data []byte) (version uint16, typeID uint32, payload []byte, err error) {
+	var env Envelope
+	if err := Unmarshal(data, &env); err != nil {
+		return 0, 0, nil, fmt.Errorf("error unmarshaling envelope: %w", err)
+	}
+	return env.Version, env.Type, env.Payload, nil
+}
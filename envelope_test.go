@@ -0,0 +1,58 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	type LoginV1 struct {
+		User string
+	}
+	type LoginV2 struct {
+		User  string
+		Token [8]byte `binary:"8"`
+	}
+
+	data, err := MarshalEnvelope(2, 0x10, LoginV2{User: "bob", Token: [8]byte{1, 2, 3}})
+	assert.NoError(t, err)
+
+	version, typeID, payload, err := UnmarshalEnvelope(data)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(2), version)
+	assert.Equal(t, uint32(0x10), typeID)
+
+	// The caller dispatches on the version to pick the payload type
+	switch version {
+	case 1:
+		var msg LoginV1
+		assert.NoError(t, Unmarshal(payload, &msg))
+	case 2:
+		var msg LoginV2
+		assert.NoError(t, Unmarshal(payload, &msg))
+		assert.Equal(t, "bob", msg.User)
+		assert.Equal(t, [8]byte{1, 2, 3}, msg.Token)
+	default:
+		t.Fatalf("unexpected version %d", version)
+	}
+}
+
+func TestEnvelopeWireFormat(t *testing.T) {
+	data, err := MarshalEnvelope(1, 2, uint8(0xAA))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x01, 0x00, // Version
+		0x02, 0x00, 0x00, 0x00, // Type
+		0x01, 0x00, 0x00, 0x00, // Payload length
+		0xAA, // Payload
+	}, data)
+}
+
+func TestEnvelopeErrors(t *testing.T) {
+	_, err := MarshalEnvelope(1, 1, make(chan int))
+	assert.Error(t, err)
+
+	_, _, _, err = UnmarshalEnvelope([]byte{0x01, 0x00, 0x02})
+	assert.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package binary
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Equal reports whether a and b have identical binary encodings, as produced by
+// Marshal. This is wire equality, not Go equality: values that differ only in
+// fields tagged "-" or in unexported fields are equal, two NaN floats with the
+// same bits are equal, and maps are equal when they hold the same entries since
+// their keys are written in sorted order. An error is returned if either value
+// cannot be marshaled.
+func Equal(a, b interface{}) (bool, error) {
+	dataA, err := Marshal(a)
+	if err != nil {
+		return false, fmt.Errorf("error marshaling first value: %w", err)
+	}
+	dataB, err := Marshal(b)
+	if err != nil {
+		return false, fmt.Errorf("error marshaling second value: %w", err)
+	}
+	return bytes.Equal(dataA, dataB), nil
+}
@@ -0,0 +1,51 @@
+package binary
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqual(t *testing.T) {
+	type Document struct {
+		ID    uint32
+		Attrs map[string]string
+		Cache []byte `binary:"-"`
+	}
+
+	a := Document{ID: 1, Attrs: map[string]string{"a": "1", "b": "2"}}
+	b := Document{ID: 1, Attrs: map[string]string{"b": "2", "a": "1"}}
+	equal, err := Equal(a, b)
+	assert.NoError(t, err)
+	assert.True(t, equal)
+
+	// Fields that are not encoded do not affect wire equality
+	b.Cache = []byte{1, 2, 3}
+	equal, err = Equal(a, b)
+	assert.NoError(t, err)
+	assert.True(t, equal)
+
+	b.ID = 2
+	equal, err = Equal(a, b)
+	assert.NoError(t, err)
+	assert.False(t, equal)
+
+	// NaN is not == to itself, but its encoding is identical
+	equal, err = Equal(math.NaN(), math.NaN())
+	assert.NoError(t, err)
+	assert.True(t, equal)
+
+	// Values of different types may still share an encoding
+	equal, err = Equal(uint16(1), int16(1))
+	assert.NoError(t, err)
+	assert.True(t, equal)
+}
+
+func TestEqualErrors(t *testing.T) {
+	_, err := Equal(make(chan int), uint8(1))
+	assert.ErrorContains(t, err, "first value")
+
+	_, err = Equal(uint8(1), func() {})
+	assert.ErrorContains(t, err, "second value")
+}
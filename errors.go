@@ -0,0 +1,113 @@
+package binary
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Sentinel errors for failures that callers may want to handle, to be tested
+// with errors.Is. The errors returned by this package wrap them with details.
+var (
+	// ErrTrailingData is matched by a *TrailingDataError
+	ErrTrailingData = errors.New("trailing data")
+	// ErrUnsupportedType is matched by an *UnsupportedTypeError
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrNilPointer is returned for a nil pointer that cannot be encoded or decoded into
+	ErrNilPointer = errors.New("nil pointer")
+	// ErrInvalidTag is returned for a malformed or misplaced `binary` struct tag
+	ErrInvalidTag = errors.New("invalid tag format")
+	// ErrTruncated is matched by errors for input that ends before the value is
+	// complete, as opposed to corrupt input. A stream reader can wait for more
+	// data when it sees it. These errors match io.ErrUnexpectedEOF as well.
+	ErrTruncated = errors.New("truncated data")
+)
+
+// errUnexpectedEOF is reported when the input ends in the middle of a value.
+// It reads like io.ErrUnexpectedEOF and matches both it and ErrTruncated.
+var errUnexpectedEOF error = truncatedError{}
+
+type truncatedError struct{}
+
+func (truncatedError) Error() string {
+	return io.ErrUnexpectedEOF.Error()
+}
+
+// Is reports whether target is ErrTruncated or io.ErrUnexpectedEOF
+func (truncatedError) Is(target error) bool {
+	return target == ErrTruncated || target == io.ErrUnexpectedEOF
+}
+
+// TrailingDataError is returned by Unmarshal and the other functions that
+// expect to consume all of their input when bytes remain after the value
+type TrailingDataError struct {
+	// Remaining is the number of unconsumed bytes
+	Remaining int
+}
+
+func (e *TrailingDataError) Error() string {
+	return fmt.Sprintf("warning: %d bytes of data remaining after unmarshaling", e.Remaining)
+}
+
+// Is reports whether target is ErrTrailingData
+func (e *TrailingDataError) Is(target error) bool {
+	return target == ErrTrailingData
+}
+
+// UnsupportedTypeError is returned for a value whose kind cannot be encoded or decoded
+type UnsupportedTypeError struct {
+	// Kind is the kind of the unsupported value, e.g. reflect.Chan
+	Kind reflect.Kind
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("unsupported type: %s", e.Kind)
+}
+
+// Is reports whether target is ErrUnsupportedType
+func (e *UnsupportedTypeError) Is(target error) bool {
+	return target == ErrUnsupportedType
+}
+
+// FieldError reports a failure to encode or decode a value nested inside a
+// struct, slice, array or map, along with the path to the failing value
+type FieldError struct {
+	// Path locates the failing value, e.g. "Address.Street" or "Items[3].ID".
+	// Map values are identified by their key, e.g. "Scores[alice]".
+	Path string
+	// Err is the underlying cause
+	Err error
+
+	op string // "encoding", "decoding", "marshaling" or "unmarshaling"
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("error %s field %s: %v", e.op, e.Path, e.Err)
+}
+
+// Unwrap returns the underlying cause
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// wrapFieldError attaches a path segment (a field name or an "[index]") to err.
+// Errors from nested values already carry the rest of the path, so the segment
+// is prepended to it instead of wrapping the error again.
+func wrapFieldError(op, segment string, err error) error {
+	if fe, ok := err.(*FieldError); ok {
+		if strings.HasPrefix(fe.Path, "[") {
+			fe.Path = segment + fe.Path
+		} else {
+			fe.Path = segment + "." + fe.Path
+		}
+		return fe
+	}
+	return &FieldError{Path: segment, Err: err, op: op}
+}
+
+// indexSegment returns the path segment of a slice, array or map element
+func indexSegment(index interface{}) string {
+	return fmt.Sprintf("[%v]", index)
+}
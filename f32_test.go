@@ -0,0 +1,52 @@
+package binary
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloat32Tag(t *testing.T) {
+	type Reading struct {
+		Value float64 `binary:"f32"`
+		Exact float64
+	}
+
+	original := Reading{Value: math.Pi, Exact: math.Pi}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// 4 bytes for the narrowed value, 8 for the untagged one
+	assert.Len(t, data, 4+8)
+	assert.Equal(t, math.Float32bits(float32(math.Pi)), uint32(data[0])|uint32(data[1])<<8|uint32(data[2])<<16|uint32(data[3])<<24)
+
+	var decoded Reading
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, float64(float32(math.Pi)), decoded.Value)
+	assert.InEpsilon(t, math.Pi, decoded.Value, 1e-7)
+	assert.Equal(t, math.Pi, decoded.Exact)
+
+	// Values beyond the float32 range become infinite
+	data, err = Marshal(Reading{Value: math.MaxFloat64})
+	assert.NoError(t, err)
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.True(t, math.IsInf(decoded.Value, 1))
+}
+
+func TestFloat32TagElements(t *testing.T) {
+	type Series struct {
+		Points []float64  `binary:"f32"`
+		Pair   [2]float64 `binary:"f32"`
+		Narrow float32    `binary:"f32"`
+	}
+
+	original := Series{Points: []float64{0.5, -1.25, 3}, Pair: [2]float64{1.5, 2}, Narrow: 0.25}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Len(t, data, 4+3*4+2*4+4)
+
+	var decoded Series
+	assert.NoError(t, Unmarshal(data, &decoded))
+	// These values are exact in float32
+	assert.Equal(t, original, decoded)
+}
@@ -0,0 +1,111 @@
+package binary
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pathStreet struct {
+	Number uint16
+	Name   string
+}
+
+type pathAddress struct {
+	City   string
+	Street pathStreet
+}
+
+type pathUser struct {
+	ID      uint32
+	Address pathAddress
+}
+
+func TestDecodeErrorPath(t *testing.T) {
+	data, err := Marshal(pathUser{ID: 1, Address: pathAddress{City: "Paris", Street: pathStreet{Number: 5, Name: "Rivoli"}}})
+	assert.NoError(t, err)
+
+	// Cut the data inside the street name
+	var decoded pathUser
+	err = Unmarshal(data[:len(data)-2], &decoded)
+	assert.Error(t, err)
+
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "Address.Street.Name", fieldErr.Path)
+	assert.Contains(t, fieldErr.Err.Error(), "exceeds remaining data")
+	assert.Contains(t, err.Error(), "error decoding field Address.Street.Name: ")
+}
+
+func TestDecodeErrorPathWithIndex(t *testing.T) {
+	type Item struct {
+		ID   uint32
+		Tags []string
+	}
+	type Order struct {
+		Items []Item
+	}
+
+	data, err := Marshal(Order{Items: []Item{{ID: 1}, {ID: 2, Tags: []string{"a", "b"}}}})
+	assert.NoError(t, err)
+
+	var decoded Order
+	err = Unmarshal(data[:len(data)-1], &decoded)
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "Items[1].Tags[1]", fieldErr.Path)
+}
+
+func TestEncodeErrorPath(t *testing.T) {
+	type Inner struct {
+		Handlers map[string]func()
+	}
+	type Outer struct {
+		Groups []Inner
+	}
+
+	_, err := Marshal(Outer{Groups: []Inner{{}, {}, {Handlers: map[string]func(){"on": nil}}}})
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "Groups[2].Handlers[on]", fieldErr.Path)
+	assert.Equal(t, &UnsupportedTypeError{Kind: reflect.Func}, fieldErr.Err)
+	assert.Contains(t, err.Error(), "error encoding field Groups[2].Handlers[on]: ")
+}
+
+func TestSentinelErrors(t *testing.T) {
+	_, err := Marshal(make(chan int))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	var unsupported *UnsupportedTypeError
+	if assert.ErrorAs(t, err, &unsupported) {
+		assert.Equal(t, reflect.Chan, unsupported.Kind)
+	}
+	assert.EqualError(t, err, "error marshaling value: unsupported type: chan")
+
+	var nilPtr *uint32
+	assert.ErrorIs(t, Unmarshal([]byte{1, 0, 0, 0}, nilPtr), ErrNilPointer)
+	_, err = Marshal(nilPtr)
+	assert.ErrorIs(t, err, ErrNilPointer)
+	assert.EqualError(t, err, "cannot encode nil pointer")
+
+	type BadTag struct {
+		Name string `binary:"prefix:3"`
+	}
+	_, err = Marshal(BadTag{})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	assert.ErrorIs(t, Unmarshal([]byte{0, 0, 0, 0}, &BadTag{}), ErrInvalidTag)
+
+	type Misplaced struct {
+		Data []byte `binary:"rest"`
+		Tail uint8
+	}
+	_, err = Marshal(Misplaced{})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+
+	// Each function that expects to consume all input reports trailing data the same way
+	var v uint8
+	assert.ErrorIs(t, Unmarshal([]byte{1, 2}, &v), ErrTrailingData)
+	assert.ErrorIs(t, UnmarshalGroup([]byte{1, 2}, &v, "g"), ErrTrailingData)
+	assert.False(t, errors.Is(Unmarshal([]byte{1}, &v), ErrTrailingData))
+}
@@ -0,0 +1,61 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedCountStringSlice(t *testing.T) {
+	type Names struct {
+		Values []string `binary:"3"`
+		After  uint8
+	}
+
+	tests := []struct {
+		name     string
+		values   []string
+		expected []string
+		size     int
+	}{
+		// Missing elements are padded with empty strings, each a bare 4-byte zero prefix
+		{"one", []string{"ab"}, []string{"ab", "", ""}, 4 + 2 + 4 + 4},
+		{"three", []string{"a", "", "xyz"}, []string{"a", "", "xyz"}, 4 + 1 + 4 + 4 + 3},
+		// Extra elements are dropped
+		{"five", []string{"a", "b", "c", "d", "e"}, []string{"a", "b", "c"}, 3 * (4 + 1)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := Marshal(Names{Values: test.values, After: 9})
+			assert.NoError(t, err)
+			// No count prefix is written, and the field after the strings follows directly
+			assert.Len(t, data, test.size+1)
+			assert.Equal(t, byte(9), data[len(data)-1])
+
+			var decoded Names
+			assert.NoError(t, Unmarshal(data, &decoded))
+			assert.Equal(t, test.expected, decoded.Values)
+			assert.Equal(t, uint8(9), decoded.After)
+		})
+	}
+}
+
+func TestFixedCountStringSlicePaddingBytes(t *testing.T) {
+	type Names struct {
+		Values []string `binary:"3"`
+	}
+
+	data, err := Marshal(Names{Values: []string{"hi"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		2, 0, 0, 0, 'h', 'i',
+		0, 0, 0, 0,
+		0, 0, 0, 0,
+	}, data)
+
+	// Decoding into a longer slice still yields exactly three elements
+	decoded := Names{Values: []string{"old", "old", "old", "old"}}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, []string{"hi", "", ""}, decoded.Values)
+}
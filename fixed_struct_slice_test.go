@@ -0,0 +1,64 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedCountItem struct {
+	ID    uint16
+	Label string
+	Tags  []string
+}
+
+type fixedCountOrder struct {
+	Items []fixedCountItem `binary:"3"`
+	Total uint32
+}
+
+func TestFixedCountStructSlice(t *testing.T) {
+	original := fixedCountOrder{
+		Items: []fixedCountItem{
+			{ID: 1, Label: "a"},
+			{ID: 2, Label: "a much longer label", Tags: []string{"x", "yz"}},
+			{ID: 3, Label: ""},
+		},
+		Total: 42,
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	// Exactly three items are decoded, each using its own framing, and the
+	// field after the slice is found at the right offset
+	var decoded fixedCountOrder
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Items, 3)
+	assert.Equal(t, original.Items[0].Label, decoded.Items[0].Label)
+	assert.Equal(t, original.Items[1], decoded.Items[1])
+	assert.Equal(t, uint32(42), decoded.Total)
+}
+
+func TestFixedCountStructSlicePadding(t *testing.T) {
+	// Missing items are written as zero values, extra items are dropped
+	short := fixedCountOrder{Items: []fixedCountItem{{ID: 7, Label: "only"}}, Total: 1}
+	data, err := Marshal(short)
+	assert.NoError(t, err)
+
+	var decoded fixedCountOrder
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Items, 3)
+	assert.Equal(t, "only", decoded.Items[0].Label)
+	assert.Equal(t, uint16(0), decoded.Items[1].ID)
+	assert.Equal(t, "", decoded.Items[2].Label)
+	assert.Equal(t, uint32(1), decoded.Total)
+
+	long := fixedCountOrder{Items: make([]fixedCountItem, 5), Total: 2}
+	long.Items[3].Label = "dropped"
+	data, err = Marshal(long)
+	assert.NoError(t, err)
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Items, 3)
+	assert.Equal(t, uint32(2), decoded.Total)
+}
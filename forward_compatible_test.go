@@ -0,0 +1,56 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userV1 struct {
+	ID   uint32
+	Name string
+}
+
+func (*userV1) ForwardCompatible() {}
+
+type userV2 struct {
+	ID    uint32
+	Name  string
+	Email string
+	Age   uint8
+}
+
+func TestForwardCompatibleIgnoresAppendedFields(t *testing.T) {
+	data, err := Marshal(userV2{ID: 7, Name: "ann", Email: "ann@example.com", Age: 30})
+	assert.NoError(t, err)
+
+	var decoded userV1
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, userV1{ID: 7, Name: "ann"}, decoded)
+
+	// Without the marker the extra bytes are still an error
+	type strictV1 struct {
+		ID   uint32
+		Name string
+	}
+	var strict strictV1
+	assert.ErrorIs(t, Unmarshal(data, &strict), ErrTrailingData)
+
+	// UnmarshalPartial keeps reporting the remaining bytes
+	remaining, err := UnmarshalPartial(data, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, len("ann@example.com")+4+1, remaining)
+}
+
+func TestForwardCompatibleStillValidatesKnownFields(t *testing.T) {
+	data, err := Marshal(userV2{ID: 1, Name: "bob"})
+	assert.NoError(t, err)
+
+	// Data too short for the known fields is an error as usual
+	var decoded userV1
+	assert.Error(t, Unmarshal(data[:5], &decoded))
+
+	// The marker applies to codecs and UnmarshalContext too
+	assert.NoError(t, NewCodec().Unmarshal(data, &decoded))
+	assert.Equal(t, userV1{ID: 1, Name: "bob"}, decoded)
+}
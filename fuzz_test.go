@@ -0,0 +1,84 @@
+package binary
+
+import (
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+// fuzzMessage exercises most field kinds and tags the decoder supports
+type fuzzMessage struct {
+	ID       uint64 `binary:"varint"`
+	Flags    [2]bool
+	Small    bool `binary:"bits"`
+	Large    bool `binary:"bits"`
+	Name     string
+	Label    string `binary:"cstr:8"`
+	Code     string `binary:"prefix:1"`
+	Fixed    [4]byte
+	Data     []byte
+	Values   []int32
+	Packed   []bool `binary:"packed"`
+	Matrix   [2][2]float64
+	Ratio    float32 `binary:"f32"`
+	Amount   float64 `binary:"decimal"`
+	Nested   *fuzzMessage
+	Children []*fuzzMessage
+	Attrs    map[string]uint16
+	Keys     map[interface{}]uint8
+	Any      interface{}
+	When     time.Time
+	Addr     net.IP
+	Big      *big.Int
+	Rat      *big.Rat
+	Custom   CustomType
+	Count    uint8
+	Items    []uint16 `binary:"lenfrom:Count"`
+}
+
+// fuzzFrame ends a fuzzMessage with a Raw field, which is only allowed at the top level
+type fuzzFrame struct {
+	fuzzMessage
+	Tail Raw
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	seed := fuzzFrame{fuzzMessage{
+		ID:       300,
+		Flags:    [2]bool{true, false},
+		Small:    true,
+		Name:     "name",
+		Label:    "label",
+		Code:     "c",
+		Data:     []byte{1, 2, 3},
+		Values:   []int32{-1, 2},
+		Packed:   []bool{true, false, true},
+		Matrix:   [2][2]float64{{1, 2}, {3, 4}},
+		Ratio:    0.5,
+		Amount:   12.25,
+		Nested:   &fuzzMessage{Name: "child"},
+		Children: []*fuzzMessage{{ID: 1}},
+		Attrs:    map[string]uint16{"k": 1},
+		Any:      Circle{Radius: 2},
+		When:     time.Unix(1700000000, 0).UTC(),
+		Addr:     net.IPv4(10, 0, 0, 1),
+		Big:      big.NewInt(-12345),
+		Rat:      big.NewRat(1, 3),
+		Custom:   CustomType{Value: "x"},
+		Items:    []uint16{1, 2},
+	}, Raw{9, 9}}
+	f.Add(MustMarshal(seed))
+	f.Add(MustMarshal(fuzzFrame{}))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Malformed input must be reported as an error, never as a panic
+		var msg fuzzFrame
+		_ = Unmarshal(data, &msg)
+		var partial fuzzFrame
+		_, _ = UnmarshalPartial(data, &partial)
+		var value interface{}
+		_ = Unmarshal(data, &value)
+	})
+}
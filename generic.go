@@ -0,0 +1,14 @@
+package binary
+
+// MarshalT is a type-safe wrapper around Marshal
+func MarshalT[T any](v T) ([]byte, error) {
+	return Marshal(v)
+}
+
+// UnmarshalT deserializes binary data into a new value of type T.
+// Like Unmarshal, it returns an error if bytes remain after the value.
+func UnmarshalT[T any](data []byte) (T, error) {
+	var v T
+	err := Unmarshal(data, &v)
+	return v, err
+}
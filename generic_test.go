@@ -0,0 +1,47 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalTUnmarshalT(t *testing.T) {
+	person := examplePerson()
+
+	data, err := MarshalT(person)
+	assert.NoError(t, err)
+
+	expected, err := Marshal(person)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, data)
+
+	v, err := UnmarshalT[Person](data)
+	assert.NoError(t, err)
+	assert.Equal(t, person.Name, v.Name)
+	assert.Equal(t, person.Email, v.Email)
+	assert.Equal(t, person.ID, v.ID)
+	assert.Equal(t, person.Values, v.Values)
+	assert.Equal(t, person.HaveChild, v.HaveChild)
+}
+
+func TestUnmarshalTTypes(t *testing.T) {
+	data, err := MarshalT([]uint16{1, 2, 3})
+	assert.NoError(t, err)
+	slice, err := UnmarshalT[[]uint16](data)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{1, 2, 3}, slice)
+
+	// Pointer types allocate the pointed-to value
+	ptr, err := UnmarshalT[*[]uint16](data)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{1, 2, 3}, *ptr)
+
+	// Custom unmarshalers are honored through the pointer
+	custom, err := UnmarshalT[CustomType]([]byte("custom:v"))
+	assert.NoError(t, err)
+	assert.Equal(t, "v", custom.Value)
+
+	_, err = UnmarshalT[uint32]([]byte{1, 2})
+	assert.Error(t, err)
+}
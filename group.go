@@ -0,0 +1,63 @@
+package binary
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// MarshalGroup serializes a value like Marshal, but only includes struct fields
+// that have no group or belong to the given group. A field joins a group with a
+// "group:name" tag option, e.g. `binary:"group:internal"`, and can be part of
+// several groups by repeating the option. This lets one struct serve several
+// wire formats. Marshal and Unmarshal ignore groups and process all fields.
+func MarshalGroup(v interface{}, group string) ([]byte, error) {
+	if group == "" {
+		return nil, fmt.Errorf("group name must not be empty")
+	}
+
+	var buf bytes.Buffer
+	if err := encodeValue(v, &encodeState{w: &buf, group: group}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalGroup deserializes data written by MarshalGroup with the same group.
+// Fields outside the group are left untouched. Like Unmarshal, it returns an
+// error if bytes remain after the value.
+func UnmarshalGroup(data []byte, v interface{}, group string) error {
+	if group == "" {
+		return fmt.Errorf("group name must not be empty")
+	}
+	if unmarshaler, ok := asUnmarshaler(v); ok {
+		return unmarshaler.UnmarshalBinary(data)
+	}
+
+	r := bytes.NewReader(data)
+	if err := decodeValue(v, &decodeState{Reader: r, group: group}); err != nil {
+		return err
+	}
+	if r.Len() > 0 {
+		return &TrailingDataError{Remaining: r.Len()}
+	}
+	return nil
+}
+
+// inGroup reports whether a field with the given tag is processed when encoding
+// or decoding the active group, and returns the tag without its group options
+func inGroup(tag string, active string) (bool, string) {
+	if !strings.Contains(tag, "group:") {
+		return true, tag
+	}
+	included := active == ""
+	var options []string
+	for _, option := range strings.Split(tag, ",") {
+		if name, ok := strings.CutPrefix(option, "group:"); ok {
+			included = included || name == active
+			continue
+		}
+		options = append(options, option)
+	}
+	return included, strings.Join(options, ",")
+}
@@ -0,0 +1,68 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type groupedAccount struct {
+	ID       uint32
+	Name     string
+	Balance  uint64 `binary:"group:internal"`
+	Notes    string `binary:"group:internal,8"`
+	Avatar   []byte `binary:"group:public"`
+	AuditRef uint16 `binary:"group:internal,group:audit"`
+}
+
+func exampleGroupedAccount() groupedAccount {
+	return groupedAccount{ID: 1, Name: "ann", Balance: 500, Notes: "vip", Avatar: []byte{9}, AuditRef: 7}
+}
+
+func TestMarshalGroupLayouts(t *testing.T) {
+	account := exampleGroupedAccount()
+
+	public, err := MarshalGroup(account, "public")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 0, 0, 0, 3, 0, 0, 0, 'a', 'n', 'n', 1, 0, 0, 0, 9}, public)
+
+	internal, err := MarshalGroup(account, "internal")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		1, 0, 0, 0, 3, 0, 0, 0, 'a', 'n', 'n',
+		244, 1, 0, 0, 0, 0, 0, 0,
+		'v', 'i', 'p', 0, 0, 0, 0, 0,
+		7, 0,
+	}, internal)
+
+	audit, err := MarshalGroup(account, "audit")
+	assert.NoError(t, err)
+	assert.Len(t, audit, 13)
+
+	// Without a group, all fields are included
+	all, err := Marshal(account)
+	assert.NoError(t, err)
+	assert.Len(t, all, 34)
+}
+
+func TestUnmarshalGroup(t *testing.T) {
+	account := exampleGroupedAccount()
+
+	public, err := MarshalGroup(account, "public")
+	assert.NoError(t, err)
+	var decodedPublic groupedAccount
+	assert.NoError(t, UnmarshalGroup(public, &decodedPublic, "public"))
+	assert.Equal(t, groupedAccount{ID: 1, Name: "ann", Avatar: []byte{9}}, decodedPublic)
+
+	internal, err := MarshalGroup(account, "internal")
+	assert.NoError(t, err)
+	var decodedInternal groupedAccount
+	assert.NoError(t, UnmarshalGroup(internal, &decodedInternal, "internal"))
+	assert.Equal(t, groupedAccount{ID: 1, Name: "ann", Balance: 500, Notes: "vip", AuditRef: 7}, decodedInternal)
+
+	// Decoding with the wrong group leaves data unconsumed
+	assert.Error(t, UnmarshalGroup(internal, &decodedPublic, "public"))
+
+	_, err = MarshalGroup(account, "")
+	assert.Error(t, err)
+}
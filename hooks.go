@@ -0,0 +1,36 @@
+package binary
+
+import "reflect"
+
+var preMarshalerType = reflect.TypeOf((*PreMarshaler)(nil)).Elem()
+
+// callPreMarshal calls the PreMarshal hook of a struct and returns the value to encode.
+// A hook with a pointer receiver needs an addressable value; when the struct is not
+// addressable, e.g. because it was passed to Marshal by value, the hook runs on a
+// copy, so the caller's value is left untouched.
+func callPreMarshal(val reflect.Value) (reflect.Value, error) {
+	typ := val.Type()
+	if typ.Implements(preMarshalerType) {
+		return val, val.Interface().(PreMarshaler).PreMarshal()
+	}
+	if !reflect.PointerTo(typ).Implements(preMarshalerType) {
+		return val, nil
+	}
+	if !val.CanAddr() {
+		copied := reflect.New(typ).Elem()
+		copied.Set(val)
+		val = copied
+	}
+	return val, val.Addr().Interface().(PreMarshaler).PreMarshal()
+}
+
+// callPostUnmarshal calls the PostUnmarshal hook of a decoded struct, if it has one
+func callPostUnmarshal(val reflect.Value) error {
+	if val.CanAddr() {
+		val = val.Addr()
+	}
+	if hook, ok := val.Interface().(PostUnmarshaler); ok {
+		return hook.PostUnmarshal()
+	}
+	return nil
+}
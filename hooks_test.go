@@ -0,0 +1,86 @@
+package binary
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sortedSet struct {
+	Values []uint16
+}
+
+func (s *sortedSet) PreMarshal() error {
+	sort.Slice(s.Values, func(i, j int) bool { return s.Values[i] < s.Values[j] })
+	return nil
+}
+
+type versionedRecord struct {
+	Version uint8
+	Set     sortedSet
+}
+
+func (r *versionedRecord) PostUnmarshal() error {
+	if r.Version == 0 || r.Version > 2 {
+		return fmt.Errorf("unsupported version %d", r.Version)
+	}
+	return nil
+}
+
+func TestPreMarshalNormalizes(t *testing.T) {
+	set := sortedSet{Values: []uint16{3, 1, 2}}
+
+	// Marshal by value sorts a copy and leaves the caller's value untouched
+	data, err := Marshal(set)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{3, 0, 0, 0, 1, 0, 2, 0, 3, 0}, data)
+
+	// Marshal by pointer runs the hook on the caller's value
+	data2, err := Marshal(&set)
+	assert.NoError(t, err)
+	assert.Equal(t, data, data2)
+	assert.Equal(t, []uint16{1, 2, 3}, set.Values)
+
+	// Nested structs run their hooks too
+	data, err = Marshal(versionedRecord{Version: 1, Set: sortedSet{Values: []uint16{9, 8}}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 0, 0, 0, 8, 0, 9, 0}, data)
+}
+
+func TestPostUnmarshalValidates(t *testing.T) {
+	var decoded versionedRecord
+	assert.NoError(t, Unmarshal([]byte{2, 0, 0, 0, 0}, &decoded))
+	assert.Equal(t, uint8(2), decoded.Version)
+
+	err := Unmarshal([]byte{7, 0, 0, 0, 0}, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported version 7")
+
+	// Hooks of nested values propagate their errors with the field path
+	type Wrapper struct {
+		Records []versionedRecord
+	}
+	data, err := Marshal(Wrapper{Records: []versionedRecord{{Version: 1}, {Version: 3}}})
+	assert.NoError(t, err)
+	err = Unmarshal(data, &Wrapper{})
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "Records[1]", fieldErr.Path)
+}
+
+type failingPreMarshal struct {
+	Value uint8
+}
+
+func (failingPreMarshal) PreMarshal() error {
+	return errors.New("not ready")
+}
+
+func TestPreMarshalError(t *testing.T) {
+	_, err := Marshal(failingPreMarshal{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not ready")
+}
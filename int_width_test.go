@@ -0,0 +1,101 @@
+package binary
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntEncodedAsEightBytes(t *testing.T) {
+	data, err := Marshal(int(-2))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, data)
+
+	data, err = Marshal(uint(1))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 0, 0, 0, 0, 0, 0, 0}, data)
+
+	data, err = Marshal(uintptr(2))
+	assert.NoError(t, err)
+	assert.Len(t, data, 8)
+}
+
+func TestIntUintRoundTrip(t *testing.T) {
+	type Counters struct {
+		Signed   int
+		Unsigned uint
+		Pointer  uintptr
+		Min      int
+	}
+
+	original := Counters{
+		Signed:   -123456789,
+		Unsigned: 1 << 40,
+		Pointer:  0xDEADBEEF,
+		Min:      math.MinInt,
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Len(t, data, 32)
+
+	var decoded Counters
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestIntSlicesAndArraysUseEightBytes(t *testing.T) {
+	ints := []int{-1, 0, 1 << 40}
+	data, err := Marshal(ints)
+	assert.NoError(t, err)
+	// Count prefix plus three 8-byte elements
+	assert.Len(t, data, 4+3*8)
+	assert.Equal(t, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, data[4:12])
+	var decodedInts []int
+	assert.NoError(t, Unmarshal(data, &decodedInts))
+	assert.Equal(t, ints, decodedInts)
+
+	uints := [3]uint{1, 1 << 33, math.MaxUint32}
+	data, err = Marshal(uints)
+	assert.NoError(t, err)
+	assert.Len(t, data, 3*8)
+	var decodedUints [3]uint
+	assert.NoError(t, Unmarshal(data, &decodedUints))
+	assert.Equal(t, uints, decodedUints)
+}
+
+func TestIntElementsMatchScalarFields(t *testing.T) {
+	type Scalars struct {
+		A int
+		B int
+	}
+	type Nested struct {
+		Matrix [][]int
+		Fixed  []uint `binary:"2"`
+		Pairs  [2][2]int
+	}
+
+	// A slice element has the same encoding as a struct field of the same type
+	fromFields, err := Marshal(Scalars{A: -5, B: 7})
+	assert.NoError(t, err)
+	fromArray, err := Marshal([2]int{-5, 7})
+	assert.NoError(t, err)
+	assert.Equal(t, fromFields, fromArray)
+
+	original := Nested{
+		Matrix: [][]int{{1, -2}, {}, {math.MaxInt}},
+		Fixed:  []uint{9},
+		Pairs:  [2][2]int{{1, 2}, {-3, -4}},
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// Prefixes: 4 for Matrix and 4 for each of its 3 rows; all elements are 8 bytes
+	assert.Equal(t, 0, (len(data)-4*4)%8)
+
+	var decoded Nested
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, []uint{9, 0}, decoded.Fixed)
+	decoded.Fixed = original.Fixed
+	assert.Equal(t, original, decoded)
+}
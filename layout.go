@@ -0,0 +1,55 @@
+package binary
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// FieldLayout describes where a struct field is placed in the encoded output of a value
+type FieldLayout struct {
+	Name   string // Go field name
+	Type   string // Go type of the field
+	Offset int    // byte offset of the field from the start of the encoded value
+	Length int    // number of bytes the field occupies, including any length prefix
+}
+
+// Layout returns the byte layout of the top-level fields of a struct value as
+// Marshal would encode it. Unlike a static description of the type, offsets and
+// lengths reflect the actual data, so variable-length fields are resolved.
+// Skipped fields (unexported or tagged "-") are not reported. A struct with its
+// own MarshalBinary method is reported as a single entry named "-" that covers
+// the bytes it writes.
+func Layout(v interface{}) ([]FieldLayout, error) {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil, fmt.Errorf("cannot compute layout of %w", ErrNilPointer)
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("layout requires a struct value, got %s", val.Kind())
+	}
+
+	// Encode like Marshal, which uses a custom marshaler of the value first
+	layout := []FieldLayout{}
+	buf := &encodeState{w: io.Discard, layout: &layout}
+	if err := encodeValue(v, buf); err != nil {
+		return nil, fmt.Errorf("error computing layout: %w", err)
+	}
+	if len(layout) == 0 && buf.n > 0 {
+		layout = append(layout, FieldLayout{Name: "-", Type: val.Type().String(), Length: int(buf.n)})
+	}
+	return layout, nil
+}
+
+// recordField appends the layout of a field that was encoded starting at offset
+func (e *encodeState) recordField(field reflect.StructField, offset int64) {
+	*e.layout = append(*e.layout, FieldLayout{
+		Name:   field.Name,
+		Type:   field.Type.String(),
+		Offset: int(offset),
+		Length: int(e.n - offset),
+	})
+}
@@ -0,0 +1,83 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayout(t *testing.T) {
+	type Inner struct {
+		X uint16
+		Y uint16
+	}
+	type Message struct {
+		ID      uint32
+		Name    string
+		Code    string `binary:"4"`
+		Skipped uint64 `binary:"-"`
+		Items   []uint16
+		Custom  CustomType
+		Inner   Inner
+		hidden  uint8
+	}
+
+	msg := Message{
+		ID:     1,
+		Name:   "hello",
+		Code:   "AB",
+		Items:  []uint16{1, 2, 3},
+		Custom: CustomType{Value: "v"},
+		Inner:  Inner{X: 1, Y: 2},
+		hidden: 3,
+	}
+
+	layout, err := Layout(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldLayout{
+		{Name: "ID", Type: "uint32", Offset: 0, Length: 4},
+		{Name: "Name", Type: "string", Offset: 4, Length: 9},
+		{Name: "Code", Type: "string", Offset: 13, Length: 4},
+		{Name: "Items", Type: "[]uint16", Offset: 17, Length: 10},
+		{Name: "Custom", Type: "binary.CustomType", Offset: 27, Length: 12},
+		{Name: "Inner", Type: "binary.Inner", Offset: 39, Length: 4},
+	}, layout)
+
+	// The layout covers exactly the marshaled bytes
+	data, err := Marshal(msg)
+	assert.NoError(t, err)
+	last := layout[len(layout)-1]
+	assert.Equal(t, len(data), last.Offset+last.Length)
+
+	// Pointers to structs are accepted as well
+	ptrLayout, err := Layout(&msg)
+	assert.NoError(t, err)
+	assert.Equal(t, layout, ptrLayout)
+}
+
+func TestLayoutErrors(t *testing.T) {
+	_, err := Layout(uint32(1))
+	assert.Error(t, err)
+
+	var nilPtr *struct{ A uint8 }
+	_, err = Layout(nilPtr)
+	assert.Error(t, err)
+
+	_, err = Layout(struct{ C chan int }{})
+	assert.Error(t, err)
+}
+
+func TestLayoutCustomMarshaler(t *testing.T) {
+	// The layout follows the bytes Marshal writes, not the struct fields
+	value := CustomType{Value: "hello"}
+	data, err := Marshal(value)
+	assert.NoError(t, err)
+
+	layout, err := Layout(value)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldLayout{{Name: "-", Type: "binary.CustomType", Offset: 0, Length: len(data)}}, layout)
+
+	ptrLayout, err := Layout(&value)
+	assert.NoError(t, err)
+	assert.Equal(t, layout, ptrLayout)
+}
@@ -0,0 +1,131 @@
+package binary
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// lenFromPrefix starts the tag option of a field whose length is stored in an
+// earlier sibling field, e.g. `binary:"lenfrom:Count"`. The field is written
+// without a length prefix: its length, the element count of a slice or the
+// byte length of a string, is written as the value of the sibling field
+// instead, and read back from it when decoding.
+const lenFromPrefix = "lenfrom:"
+
+// lenFromOption returns the name of the field referenced by a "lenfrom:Name" option
+func lenFromOption(tag string) (string, bool) {
+	if !strings.Contains(tag, lenFromPrefix) {
+		return "", false
+	}
+	for option := range strings.SplitSeq(tag, ",") {
+		if name, ok := strings.CutPrefix(option, lenFromPrefix); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// withFixedLength replaces the "lenfrom:Name" option of a tag with a fixed length.
+// The length is exact, so strings keep their trailing zeros like with "raw:N".
+func withFixedLength(tag string, length int) string {
+	options := strings.Split(tag, ",")
+	for i, option := range options {
+		if strings.HasPrefix(option, lenFromPrefix) {
+			options[i] = "raw:" + strconv.Itoa(length)
+		}
+	}
+	return strings.Join(options, ",")
+}
+
+// lenFromSource returns the index of the integer field that holds the length of
+// the field at index i, which must be encoded before it in the same struct
+func lenFromSource(typ reflect.Type, i int, name string) (int, error) {
+	switch typ.Field(i).Type.Kind() {
+	case reflect.Slice, reflect.String:
+	default:
+		return 0, fmt.Errorf("%w: lenfrom is only supported on slices and strings, not %s", ErrInvalidTag, typ.Field(i).Type)
+	}
+	source, ok := typ.FieldByName(name)
+	if !ok || len(source.Index) != 1 || !encodedBefore(typ, source.Index[0], i) {
+		return 0, fmt.Errorf("%w: lenfrom field %s must be encoded earlier in the same struct", ErrInvalidTag, name)
+	}
+	if !isIntegerKind(source.Type.Kind()) {
+		return 0, fmt.Errorf("%w: lenfrom field %s must be an integer, not %s", ErrInvalidTag, name, source.Type)
+	}
+	return source.Index[0], nil
+}
+
+// lenFromLengths returns, for each field of a struct that holds the length of a
+// later field tagged "lenfrom", the length to write in its place
+func lenFromLengths(val reflect.Value) (map[int]int, error) {
+	typ := val.Type()
+	var lengths map[int]int
+	for i := 0; i < typ.NumField(); i++ {
+		name, ok := lenFromOption(typ.Field(i).Tag.Get("binary"))
+		if !ok {
+			continue
+		}
+		source, err := lenFromSource(typ, i, name)
+		if err != nil {
+			return nil, wrapFieldError("encoding", typ.Field(i).Name, err)
+		}
+		if lengths == nil {
+			lengths = make(map[int]int)
+		}
+		lengths[source] = val.Field(i).Len()
+	}
+	return lengths, nil
+}
+
+// lengthValue returns a value of the given integer type holding length
+func lengthValue(typ reflect.Type, length int) (reflect.Value, error) {
+	value := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.OverflowInt(int64(length)) {
+			return value, fmt.Errorf("length %d overflows %s", length, typ)
+		}
+		value.SetInt(int64(length))
+	default:
+		if value.OverflowUint(uint64(length)) {
+			return value, fmt.Errorf("length %d overflows %s", length, typ)
+		}
+		value.SetUint(uint64(length))
+	}
+	return value, nil
+}
+
+// decodedLength returns the length stored in an already decoded integer field
+// for the field it precedes. The length comes from the input, so it is validated
+// like a length prefix before the field is allocated.
+func decodedLength(buf *decodeState, source reflect.Value, field reflect.Value) (int, error) {
+	var length uint64
+	switch source.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if source.Int() < 0 {
+			return 0, fmt.Errorf("negative length %d", source.Int())
+		}
+		length = uint64(source.Int())
+	default:
+		length = source.Uint()
+	}
+	if length > math.MaxUint32 {
+		return 0, fmt.Errorf("length %d exceeds maximum supported length", length)
+	}
+
+	if field.Kind() == reflect.String || field.Type().Elem().Kind() == reflect.Uint8 {
+		return int(length), buf.checkLength(int(length))
+	}
+	if err := buf.checkCount(int(length)); err != nil {
+		return 0, err
+	}
+	// Every element occupies at least one byte, so a length larger than the
+	// remaining input cannot be satisfied
+	if remaining, ok := buf.remaining(); ok && field.Type().Elem().Size() > 0 && int(length) > remaining {
+		return 0, fmt.Errorf("length %d exceeds remaining data of %d bytes: %w", length, remaining, errUnexpectedEOF)
+	}
+	return int(length), nil
+}
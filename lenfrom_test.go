@@ -0,0 +1,114 @@
+package binary
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLenFromTag(t *testing.T) {
+	type Message struct {
+		Count uint16
+		Kind  uint8
+		Items []uint32 `binary:"lenfrom:Count"`
+	}
+
+	// Count is filled in from the slice length, whatever the struct holds
+	data, err := Marshal(Message{Count: 9, Kind: 1, Items: []uint32{5, 6}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		2, 0, // Count
+		1,          // Kind
+		5, 0, 0, 0, // Items, without a length prefix
+		6, 0, 0, 0,
+	}, data)
+
+	var decoded Message
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, Message{Count: 2, Kind: 1, Items: []uint32{5, 6}}, decoded)
+
+	data, err = Marshal(Message{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0}, data)
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, Message{Items: []uint32{}}, decoded)
+}
+
+func TestLenFromStringAndBytes(t *testing.T) {
+	type Record struct {
+		NameLen int8
+		DataLen uint32
+		Name    string `binary:"lenfrom:NameLen"`
+		Data    []byte `binary:"lenfrom:DataLen"`
+	}
+
+	original := Record{NameLen: 3, DataLen: 2, Name: "ab\x00", Data: []byte{1, 2}}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{3, 2, 0, 0, 0, 'a', 'b', 0, 1, 2}, data)
+
+	// Trailing zeros of the string are kept
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestLenFromOverflow(t *testing.T) {
+	type Message struct {
+		Count uint8
+		Items []uint16 `binary:"lenfrom:Count"`
+	}
+
+	_, err := Marshal(Message{Items: make([]uint16, 256)})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "overflows uint8")
+}
+
+func TestLenFromInvalidReference(t *testing.T) {
+	type Later struct {
+		Items []uint16 `binary:"lenfrom:Count"`
+		Count uint16
+	}
+	_, err := Marshal(Later{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must be encoded earlier")
+	assert.Error(t, Unmarshal([]byte{0, 0}, &Later{}))
+
+	type Missing struct {
+		Items []uint16 `binary:"lenfrom:Count"`
+	}
+	_, err = Marshal(Missing{})
+	assert.Error(t, err)
+
+	type NotInteger struct {
+		Count string
+		Items []uint16 `binary:"lenfrom:Count"`
+	}
+	_, err = Marshal(NotInteger{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must be an integer")
+
+	type NotSlice struct {
+		Count uint16
+		Value uint32 `binary:"lenfrom:Count"`
+	}
+	_, err = Marshal(NotSlice{})
+	assert.Error(t, err)
+}
+
+func TestLenFromDecodeValidation(t *testing.T) {
+	type Message struct {
+		Count int32
+		Items []uint32 `binary:"lenfrom:Count"`
+	}
+
+	var decoded Message
+	err := Unmarshal([]byte{0xff, 0xff, 0xff, 0xff}, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "negative length")
+
+	// A length larger than the remaining input is rejected before allocating
+	err = Unmarshal([]byte{0xff, 0xff, 0xff, 0x7f, 1, 0, 0, 0}, &decoded)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
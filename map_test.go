@@ -0,0 +1,104 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapStringUint32(t *testing.T) {
+	original := map[string]uint32{"b": 2, "a": 1, "c": 3}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	// Keys are written in sorted order
+	assert.Equal(t, []byte{
+		3, 0, 0, 0,
+		1, 0, 0, 0, 'a', 1, 0, 0, 0,
+		1, 0, 0, 0, 'b', 2, 0, 0, 0,
+		1, 0, 0, 0, 'c', 3, 0, 0, 0,
+	}, data)
+
+	var decoded map[string]uint32
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestMapUint16String(t *testing.T) {
+	original := map[uint16]string{300: "three hundred", 2: "two", 0: ""}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded map[uint16]string
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestMapDeterministicOutput(t *testing.T) {
+	type Point struct {
+		X, Y int16
+	}
+
+	ints := map[int32]bool{}
+	points := map[Point]uint8{}
+	for i := int32(-50); i < 50; i++ {
+		ints[i] = i%2 == 0
+		points[Point{int16(i), int16(-i)}] = uint8(i)
+	}
+
+	for _, m := range []interface{}{ints, points, map[bool]string{true: "t", false: "f"}} {
+		first, err := Marshal(m)
+		assert.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			again, err := Marshal(m)
+			assert.NoError(t, err)
+			assert.Equal(t, first, again)
+		}
+	}
+
+	data, err := Marshal(points)
+	assert.NoError(t, err)
+	var decoded map[Point]uint8
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, points, decoded)
+}
+
+func TestMapNestedInStruct(t *testing.T) {
+	type Config struct {
+		Name    string
+		Limits  map[string]uint32
+		Aliases map[uint16][]string
+		Empty   map[string]uint8
+	}
+
+	original := Config{
+		Name:    "service",
+		Limits:  map[string]uint32{"cpu": 4, "memory": 2048},
+		Aliases: map[uint16][]string{1: {"one", "uno"}, 2: nil},
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	size, err := Size(original)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), size)
+
+	var decoded Config
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original.Name, decoded.Name)
+	assert.Equal(t, original.Limits, decoded.Limits)
+	assert.Equal(t, []string{"one", "uno"}, decoded.Aliases[1])
+	assert.Empty(t, decoded.Aliases[2])
+	// A nil map is encoded as an empty map
+	assert.NotNil(t, decoded.Empty)
+	assert.Empty(t, decoded.Empty)
+}
+
+func TestMapHugeCountAgainstShortBuffer(t *testing.T) {
+	var decoded map[uint32]uint32
+	err := Unmarshal([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x01}, &decoded)
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalAppend(t *testing.T) {
+	person := examplePerson()
+	expected, err := Marshal(person)
+	assert.NoError(t, err)
+
+	prefix := []byte{0xAA, 0xBB}
+	out, err := MarshalAppend(prefix, person)
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte{0xAA, 0xBB}, expected...), out)
+
+	// Appending into spare capacity reuses the backing array
+	dst := make([]byte, 0, 1024)
+	out, err = MarshalAppend(dst, person)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, out)
+	assert.Equal(t, &dst[:1][0], &out[0])
+
+	// Values implementing BinaryMarshaler are appended as-is
+	out, err = MarshalAppend(nil, CustomType{Value: "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("custom:x"), out)
+}
+
+func TestMarshalAppendError(t *testing.T) {
+	dst := []byte{1, 2, 3}
+	out, err := MarshalAppend(dst, struct {
+		A uint32
+		C chan int
+	}{A: 1})
+	assert.Error(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, out)
+}
+
+func BenchmarkMarshalPerson(b *testing.B) {
+	person := examplePerson()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Marshal(person); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalAppendPerson(b *testing.B) {
+	person := examplePerson()
+	buf := make([]byte, 0, 256)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		buf, err = MarshalAppend(buf[:0], person)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// shortWriter accepts at most limit bytes and then reports an error
+type shortWriter struct {
+	limit int
+	buf   bytes.Buffer
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		w.buf.Write(p[:w.limit])
+		return w.limit, errors.New("short write")
+	}
+	w.buf.Write(p)
+	return len(p), nil
+}
+
+func TestMarshalTo(t *testing.T) {
+	type Record struct {
+		ID    uint32
+		Name  string
+		Tags  []string
+		Fixed string `binary:"8"`
+	}
+
+	original := Record{ID: 7, Name: "alpha", Tags: []string{"a", "bc"}, Fixed: "xy"}
+	expected, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	buf.WriteString("header")
+	n, err := MarshalTo(&buf, original)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(expected)), n)
+	assert.Equal(t, append([]byte("header"), expected...), buf.Bytes())
+}
+
+func TestMarshalToErrors(t *testing.T) {
+	// Marshal errors write nothing
+	var buf bytes.Buffer
+	n, err := MarshalTo(&buf, make(chan int))
+	assert.Error(t, err)
+	assert.Equal(t, int64(0), n)
+	assert.Equal(t, 0, buf.Len())
+
+	// Writer errors report the bytes actually written
+	w := &shortWriter{limit: 3}
+	n, err = MarshalTo(w, uint64(1))
+	assert.Error(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestMarshalToBytesBuffer(t *testing.T) {
+	type Record struct {
+		ID   uint16
+		Tags []string
+	}
+
+	original := Record{ID: 3, Tags: []string{"x", "yz"}}
+	expected, err := Marshal(original)
+	assert.NoError(t, err)
+
+	// A pooled buffer keeps its contents and capacity, and the value is appended in place
+	buf := bytes.NewBuffer(make([]byte, 0, 256))
+	buf.WriteString("hdr")
+	backing := buf.Bytes()[:1]
+	n, err := MarshalTo(buf, original)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(expected)), n)
+	assert.Equal(t, append([]byte("hdr"), expected...), buf.Bytes())
+	assert.Same(t, &backing[0], &buf.Bytes()[0])
+
+	// A value that fails part-way leaves the buffer as it was
+	type Broken struct {
+		ID uint16
+		Ch chan int
+	}
+	n, err = MarshalTo(buf, Broken{ID: 1})
+	assert.Error(t, err)
+	assert.Equal(t, int64(0), n)
+	assert.Equal(t, append([]byte("hdr"), expected...), buf.Bytes())
+}
@@ -0,0 +1,91 @@
+package binary
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrixUint32(t *testing.T) {
+	original := [2][3]uint32{{1, 2, 3}, {4, 5, 6}}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// Row-major order, without any length prefix
+	assert.Equal(t, []byte{
+		1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0,
+		4, 0, 0, 0, 5, 0, 0, 0, 6, 0, 0, 0,
+	}, data)
+
+	var decoded [2][3]uint32
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestMatrixFloat64(t *testing.T) {
+	type Transform struct {
+		Name   string
+		Matrix [3][3]float64
+		Scale  float32
+	}
+
+	original := Transform{
+		Name:   "rot",
+		Matrix: [3][3]float64{{0, -1, 0}, {1, 0, 0}, {0, 0, math.Pi}},
+		Scale:  2,
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Len(t, data, 4+3+9*8+4)
+	assert.Equal(t, math.Pi, math.Float64frombits(binary.LittleEndian.Uint64(data[7+8*8:])))
+
+	decoded := Transform{Matrix: [3][3]float64{{9, 9, 9}, {9, 9, 9}, {9, 9, 9}}}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestMatrixMatchesElementwiseEncoding(t *testing.T) {
+	type Celsius int16
+
+	// Named element types take the general path; the output is the same
+	fast, err := Marshal([2][2][2]int16{{{1, -2}, {3, -4}}, {{5, -6}, {7, -8}}})
+	assert.NoError(t, err)
+	general, err := Marshal([2][2][2]Celsius{{{1, -2}, {3, -4}}, {{5, -6}, {7, -8}}})
+	assert.NoError(t, err)
+	assert.Equal(t, general, fast)
+
+	// Inner byte arrays keep their length prefix
+	data, err := Marshal([2][2]byte{{1, 2}, {3, 4}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2, 0, 0, 0, 1, 2, 2, 0, 0, 0, 3, 4}, data)
+}
+
+func TestMatrixShortData(t *testing.T) {
+	var decoded [2][3]uint32
+	assert.Error(t, Unmarshal(make([]byte, 20), &decoded))
+
+	// Matrices inside maps are decoded through a temporary value
+	original := map[string][2][2]float32{"id": {{1, 0}, {0, 1}}}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	var m map[string][2][2]float32
+	assert.NoError(t, Unmarshal(data, &m))
+	assert.Equal(t, original, m)
+}
+
+func BenchmarkMarshalMatrix(b *testing.B) {
+	var m [64][64]float64
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i * j)
+		}
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Marshal(m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package binary
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHugeLengthPrefixAgainstShortBuffer(t *testing.T) {
+	data := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x01, 0x02}
+
+	var s string
+	err := Unmarshal(data, &s)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds remaining data")
+
+	var b []byte
+	err = Unmarshal(data, &b)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds remaining data")
+
+	var arr [4]byte
+	err = Unmarshal(data, &arr)
+	assert.Error(t, err)
+
+	var u []uint32
+	err = Unmarshal(data, &u)
+	assert.Error(t, err)
+
+	var nested []struct{ Name string }
+	err = Unmarshal(data, &nested)
+	assert.Error(t, err)
+}
+
+func TestHugeLengthPrefixInCustomField(t *testing.T) {
+	type Wrapper struct {
+		Custom CustomType
+	}
+
+	var decoded Wrapper
+	err := Unmarshal([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x01, 0x02}, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds remaining data")
+}
+
+func TestSliceOfZeroSizeElements(t *testing.T) {
+	original := make([]struct{}, 1000)
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded []struct{}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Len(t, decoded, 1000)
+}
+
+func TestDecoderSetMaxAllocSize(t *testing.T) {
+	type Record struct {
+		Name  string
+		Items []uint16
+	}
+
+	data, err := Marshal(Record{Name: "abcdef", Items: []uint16{1, 2, 3}})
+	assert.NoError(t, err)
+
+	// A plain io.Reader cannot report the remaining size, so only the limit applies
+	dec := NewDecoder(io.MultiReader(bytes.NewReader(data)))
+	dec.SetMaxAllocSize(4)
+	var decoded Record
+	err = dec.Decode(&decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds maximum allowed size")
+
+	dec = NewDecoder(io.MultiReader(bytes.NewReader(data)))
+	dec.SetMaxAllocSize(6)
+	assert.NoError(t, dec.Decode(&decoded))
+	assert.Equal(t, Record{Name: "abcdef", Items: []uint16{1, 2, 3}}, decoded)
+
+	// The limit counts elements for slices
+	items, err := Marshal([]uint64{1, 2, 3})
+	assert.NoError(t, err)
+	dec = NewDecoder(bytes.NewReader(items))
+	dec.SetMaxAllocSize(2)
+	var decodedItems []uint64
+	assert.Error(t, dec.Decode(&decodedItems))
+}
+
+func TestHugeLengthPrefixOnStream(t *testing.T) {
+	// A plain io.Reader cannot report the remaining size, so values are
+	// allocated as their data arrives instead of at the declared length
+	prefix := []byte{0xFF, 0xFF, 0xFF, 0xFF, 1, 2, 3, 4, 5, 6, 7, 8}
+	for _, v := range []interface{}{new(string), new([]byte), new([]uint32), new([]string), new(map[uint8]uint8)} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		err := NewDecoder(io.MultiReader(bytes.NewReader(prefix))).Decode(v)
+		runtime.ReadMemStats(&after)
+
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "%T", v)
+		assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(16<<20), "%T", v)
+	}
+
+	// Values larger than the initial allocation still decode
+	values := make([]uint32, 3*maxUnboundedAlloc/4+1)
+	for i := range values {
+		values[i] = uint32(i)
+	}
+	data, err := Marshal(values)
+	assert.NoError(t, err)
+	var decoded []uint32
+	assert.NoError(t, UnmarshalReader(io.MultiReader(bytes.NewReader(data)), &decoded))
+	assert.Equal(t, values, decoded)
+
+	text := bytes.Repeat([]byte("x"), maxUnboundedAlloc+1)
+	data, err = Marshal(text)
+	assert.NoError(t, err)
+	var decodedText []byte
+	assert.NoError(t, UnmarshalReader(io.MultiReader(bytes.NewReader(data)), &decodedText))
+	assert.Equal(t, text, decodedText)
+}
@@ -0,0 +1,62 @@
+package binary
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type listNode struct {
+	Value uint8
+	Next  *listNode
+}
+
+type nestedSlice []nestedSlice
+
+// encodedList returns the encoding of a linked list with n nodes without building it
+func encodedList(n int) []byte {
+	data := bytes.Repeat([]byte{7, 1}, n-1)
+	return append(data, 7, 0)
+}
+
+func TestMaxDepthLinkedList(t *testing.T) {
+	var head listNode
+	assert.NoError(t, Unmarshal(encodedList(defaultMaxDepth), &head))
+
+	// Hostile input implying 100k nested nodes fails cleanly instead of overflowing the stack
+	err := Unmarshal(encodedList(100000), &head)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nesting depth exceeds maximum of 1000")
+}
+
+func TestMaxDepthNestedSlices(t *testing.T) {
+	data := append(bytes.Repeat([]byte{1, 0, 0, 0}, 5000), 0, 0, 0, 0)
+	var decoded nestedSlice
+	err := Unmarshal(data, &decoded)
+	assert.ErrorContains(t, err, "nesting depth exceeds maximum")
+}
+
+func TestDecoderSetMaxDepth(t *testing.T) {
+	data := encodedList(10)
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.SetMaxDepth(5)
+	var head listNode
+	assert.ErrorContains(t, dec.Decode(&head), "nesting depth exceeds maximum of 5")
+
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.SetMaxDepth(10)
+	assert.NoError(t, dec.Decode(&head))
+	depth := 0
+	for n := &head; n != nil; n = n.Next {
+		depth++
+	}
+	assert.Equal(t, 10, depth)
+
+	// The limit applies to each value, not to the whole stream
+	dec = NewDecoder(bytes.NewReader(append(encodedList(3), encodedList(3)...)))
+	dec.SetMaxDepth(3)
+	assert.NoError(t, dec.Decode(&head))
+	assert.NoError(t, dec.Decode(&head))
+}
@@ -0,0 +1,20 @@
+package binary
+
+import "fmt"
+
+// MustMarshal is like Marshal but panics if the value cannot be encoded.
+// It simplifies test fixtures and setup code where an error is fatal anyway.
+func MustMarshal(v interface{}) []byte {
+	data, err := Marshal(v)
+	if err != nil {
+		panic(fmt.Errorf("binary: MustMarshal: %w", err))
+	}
+	return data
+}
+
+// MustUnmarshal is like Unmarshal but panics if the data cannot be decoded into v
+func MustUnmarshal(data []byte, v interface{}) {
+	if err := Unmarshal(data, v); err != nil {
+		panic(fmt.Errorf("binary: MustUnmarshal: %w", err))
+	}
+}
@@ -0,0 +1,43 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMustMarshalUnmarshal(t *testing.T) {
+	type Config struct {
+		Name string
+		Port uint16
+	}
+
+	original := Config{Name: "svc", Port: 8080}
+	data := MustMarshal(original)
+	expected, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, data)
+
+	var decoded Config
+	MustUnmarshal(data, &decoded)
+	assert.Equal(t, original, decoded)
+}
+
+func TestMustMarshalPanics(t *testing.T) {
+	assert.PanicsWithError(t, "binary: MustMarshal: error marshaling value: unsupported type: chan", func() {
+		MustMarshal(make(chan int))
+	})
+}
+
+func TestMustUnmarshalPanics(t *testing.T) {
+	var ch chan int
+	assert.Panics(t, func() {
+		MustUnmarshal([]byte{1}, &ch)
+	})
+
+	// Trailing bytes are an error, as with Unmarshal
+	var v uint8
+	assert.Panics(t, func() {
+		MustUnmarshal([]byte{1, 2}, &v)
+	})
+}
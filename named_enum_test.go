@@ -0,0 +1,68 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Status uint16
+
+const (
+	StatusUnknown Status = iota
+	StatusActive
+	StatusSuspended
+	StatusClosed = 1000
+)
+
+func TestNamedEnumType(t *testing.T) {
+	type Account struct {
+		State    Status
+		History  []Status
+		Recent   [2]Status
+		Counts   map[Status]uint32
+		Packed   Status   `binary:"varint"`
+		Fixed    []Status `binary:"3"`
+		Previous *Status
+	}
+
+	previous := StatusSuspended
+	original := Account{
+		State:    StatusActive,
+		History:  []Status{StatusUnknown, StatusActive, StatusClosed},
+		Recent:   [2]Status{StatusSuspended, StatusClosed},
+		Counts:   map[Status]uint32{StatusActive: 3, StatusClosed: 1},
+		Packed:   StatusClosed,
+		Fixed:    []Status{StatusActive},
+		Previous: &previous,
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// A named uint16 has the same encoding as its underlying type
+	assert.Equal(t, []byte{1, 0}, data[:2])
+	assert.Equal(t, []byte{3, 0, 0, 0, 0, 0, 1, 0, 0xE8, 0x03}, data[2:12])
+
+	var decoded Account
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, []Status{StatusActive, StatusUnknown, StatusUnknown}, decoded.Fixed)
+	decoded.Fixed = original.Fixed
+	assert.Equal(t, original, decoded)
+}
+
+func TestNamedEnumTopLevel(t *testing.T) {
+	data, err := Marshal([]Status{StatusClosed, StatusActive})
+	assert.NoError(t, err)
+	plain, err := Marshal([]uint16{1000, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, plain, data)
+
+	decoded, err := UnmarshalT[[]Status](data)
+	assert.NoError(t, err)
+	assert.Equal(t, []Status{StatusClosed, StatusActive}, decoded)
+
+	keys, err := Marshal(map[Status]bool{StatusClosed: true, StatusActive: false})
+	assert.NoError(t, err)
+	// Keys are sorted by value
+	assert.Equal(t, []byte{2, 0, 0, 0, 1, 0, 0, 0xE8, 0x03, 1}, keys)
+}
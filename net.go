@@ -0,0 +1,117 @@
+package binary
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+)
+
+var (
+	ipType    = reflect.TypeOf(net.IP{})
+	ipNetType = reflect.TypeOf(net.IPNet{})
+)
+
+// Address families written before an encoded net.IP
+const (
+	ipFamilyNone = 0 // nil or empty IP
+	ipFamilyV4   = 4
+	ipFamilyV6   = 6
+)
+
+// encodedIPSize is the length of an encoded net.IP: the family byte and a 16-byte address
+const encodedIPSize = 1 + net.IPv6len
+
+// encodeIP serializes a net.IP as a family byte (0 for nil, 4 or 6) followed by the
+// 16-byte form of the address, so IPv4 and IPv6 addresses have the same size on the wire
+func encodeIP(ip net.IP, buf *encodeState) error {
+	var data [encodedIPSize]byte
+	switch {
+	case len(ip) == 0:
+		data[0] = ipFamilyNone
+	case ip.To4() != nil:
+		data[0] = ipFamilyV4
+		copy(data[1:], ip.To16())
+	case len(ip) == net.IPv6len:
+		data[0] = ipFamilyV6
+		copy(data[1:], ip)
+	default:
+		return fmt.Errorf("invalid IP address length: %d", len(ip))
+	}
+	_, err := buf.Write(data[:])
+	return err
+}
+
+// decodeIP deserializes a net.IP written by encodeIP.
+// IPv4 addresses are decoded in their 4-byte form.
+func decodeIP(buf *decodeState) (net.IP, error) {
+	var data [encodedIPSize]byte
+	if err := buf.readFull(data[:]); err != nil {
+		return nil, err
+	}
+	ip := net.IP(data[1:])
+	switch data[0] {
+	case ipFamilyNone:
+		return nil, nil
+	case ipFamilyV4:
+		if ip.To4() == nil {
+			return nil, fmt.Errorf("invalid IPv4 address: %s", ip)
+		}
+		return append(net.IP(nil), ip.To4()...), nil
+	case ipFamilyV6:
+		return append(net.IP(nil), ip...), nil
+	default:
+		return nil, fmt.Errorf("invalid IP address family: %d", data[0])
+	}
+}
+
+// encodeIPNet serializes a net.IPNet as its prefix length (1 byte) followed by
+// the address encoded like a net.IP. Only canonical masks can be encoded.
+func encodeIPNet(n net.IPNet, buf *encodeState) error {
+	if len(n.IP) == 0 {
+		// The empty network, such as an unset field, is written as prefix length 0
+		// and a nil IP. A mask without an address could not be decoded.
+		if len(n.Mask) != 0 {
+			return fmt.Errorf("IP network with mask %s has no address", n.Mask)
+		}
+		if _, err := buf.Write([]byte{0}); err != nil {
+			return err
+		}
+		return encodeIP(nil, buf)
+	}
+	ones, bits := n.Mask.Size()
+	if bits == 0 {
+		return fmt.Errorf("non-canonical IP mask: %s", n.Mask)
+	}
+	if _, err := buf.Write([]byte{byte(ones)}); err != nil {
+		return err
+	}
+	return encodeIP(n.IP, buf)
+}
+
+// decodeIPNet deserializes a net.IPNet written by encodeIPNet
+func decodeIPNet(buf *decodeState, field reflect.Value) error {
+	ones, err := buf.ReadByte()
+	if err != nil {
+		return err
+	}
+	ip, err := decodeIP(buf)
+	if err != nil {
+		return err
+	}
+	if ip == nil {
+		if ones != 0 {
+			return fmt.Errorf("invalid IP network prefix length: %d", ones)
+		}
+		field.Set(reflect.ValueOf(net.IPNet{}))
+		return nil
+	}
+	bits := 8 * net.IPv6len
+	if ip.To4() != nil {
+		bits = 8 * net.IPv4len
+	}
+	if int(ones) > bits {
+		return fmt.Errorf("invalid IP network prefix length: %d", ones)
+	}
+	field.Set(reflect.ValueOf(net.IPNet{IP: ip, Mask: net.CIDRMask(int(ones), bits)}))
+	return nil
+}
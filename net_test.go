@@ -0,0 +1,106 @@
+package binary
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hostRecord struct {
+	Addr    net.IP
+	Gateway net.IP
+	Subnet  net.IPNet
+}
+
+func TestIPRoundTrip(t *testing.T) {
+	_, v4net, err := net.ParseCIDR("192.168.1.0/24")
+	assert.NoError(t, err)
+	_, v6net, err := net.ParseCIDR("2001:db8::/48")
+	assert.NoError(t, err)
+
+	records := []hostRecord{
+		{Addr: net.ParseIP("192.168.1.10"), Gateway: net.IPv4(192, 168, 1, 1), Subnet: *v4net},
+		{Addr: net.ParseIP("2001:db8::1"), Subnet: *v6net},
+	}
+
+	for _, original := range records {
+		data, err := Marshal(original)
+		assert.NoError(t, err)
+		// Two 17-byte addresses and the 18-byte network
+		assert.Len(t, data, 52)
+
+		var decoded hostRecord
+		assert.NoError(t, Unmarshal(data, &decoded))
+		assert.True(t, original.Addr.Equal(decoded.Addr))
+		assert.True(t, original.Gateway.Equal(decoded.Gateway))
+		assert.Equal(t, original.Subnet.String(), decoded.Subnet.String())
+	}
+}
+
+func TestIPWireFormat(t *testing.T) {
+	// IPv4 and IPv6 addresses both use a family byte and 16 address bytes
+	data, err := Marshal(net.IPv4(10, 0, 0, 1).To4())
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 10, 0, 0, 1}, data)
+
+	var ip net.IP
+	assert.NoError(t, Unmarshal(data, &ip))
+	assert.Equal(t, net.IP{10, 0, 0, 1}, ip)
+
+	data, err = Marshal(net.ParseIP("::1"))
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte{6}, net.ParseIP("::1")...), data)
+
+	// A nil IP is written as family 0
+	data, err = Marshal(net.IP(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, make([]byte, 17), data)
+	assert.NoError(t, Unmarshal(data, &ip))
+	assert.Nil(t, ip)
+
+	_, err = Marshal(net.IP{1, 2, 3})
+	assert.Error(t, err)
+
+	data[0] = 5
+	assert.Error(t, Unmarshal(data, &ip))
+}
+
+func TestIPNetWireFormat(t *testing.T) {
+	_, network, err := net.ParseCIDR("10.1.0.0/16")
+	assert.NoError(t, err)
+
+	data, err := Marshal(network)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(16), data[0])
+	assert.Equal(t, byte(4), data[1])
+
+	var decoded net.IPNet
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, *network, decoded)
+
+	// Non-canonical masks cannot be encoded
+	_, err = Marshal(net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.IPMask{255, 0, 255, 0}})
+	assert.Error(t, err)
+}
+
+func TestIPNetZeroValue(t *testing.T) {
+	// An unset network field round-trips as the empty network
+	type Route struct {
+		Subnet net.IPNet
+		Metric uint8
+	}
+	data, err := Marshal(Route{Metric: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, append(make([]byte, 1+encodedIPSize), 3), data)
+
+	decoded := Route{Subnet: net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)}}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, Route{Metric: 3}, decoded)
+
+	// A nil IP with a mask is rejected on both sides instead of only when decoding
+	_, err = Marshal(net.IPNet{Mask: net.CIDRMask(24, 32)})
+	assert.Error(t, err)
+	data[0] = 24
+	assert.Error(t, Unmarshal(data, &decoded))
+}
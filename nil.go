@@ -0,0 +1,42 @@
+package binary
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// hasNilPresence reports whether a value of type typ is preceded by a presence
+// byte because the codec preserves nil slices and maps. net.IP and Raw keep
+// their own framing.
+func hasNilPresence(options codecOptions, typ reflect.Type) bool {
+	return options.preserveNil && typ != ipType && typ != rawType
+}
+
+// encodeNilPresence writes the presence byte of a slice or map, 0 for nil and
+// 1 otherwise, and reports whether the value is nil and therefore complete
+func encodeNilPresence(field reflect.Value, buf *encodeState) (bool, error) {
+	if field.IsNil() {
+		_, err := buf.Write([]byte{0})
+		return true, err
+	}
+	_, err := buf.Write([]byte{1})
+	return false, err
+}
+
+// decodeNilPresence reads the presence byte written by encodeNilPresence. For a
+// nil value it sets the field to nil and reports that the value is complete.
+func decodeNilPresence(buf *decodeState, field reflect.Value) (bool, error) {
+	present, err := buf.ReadByte()
+	if err != nil {
+		return false, err
+	}
+	switch present {
+	case 0:
+		field.SetZero()
+		return true, nil
+	case 1:
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid %s presence byte: %d", field.Kind(), present)
+	}
+}
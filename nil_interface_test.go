@@ -0,0 +1,73 @@
+package binary
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNilAndNonNilInterfaceFields(t *testing.T) {
+	type Result struct {
+		Shape Shape
+		Meta  interface{}
+		Code  uint8
+	}
+
+	original := Result{Shape: Circle{Radius: 2}, Code: 7}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// Shape: presence byte, type code and the float64 radius; Meta: a single 0 byte
+	assert.Equal(t, []byte{1, 100, 0, 0, 0}, data[:5])
+	assert.Equal(t, []byte{0, 7}, data[13:])
+
+	// Decoding a nil interface clears a value left in the destination
+	decoded := Result{Meta: CustomType{Value: "stale"}}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+
+	empty, err := Marshal(Result{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0}, empty)
+}
+
+func TestNilInterfaceElements(t *testing.T) {
+	shapes := []Shape{nil, &Rect{Width: 1, Height: 2}, nil}
+	data, err := Marshal(shapes)
+	assert.NoError(t, err)
+
+	var decoded []Shape
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, shapes, decoded)
+}
+
+func TestInterfacePresenceByteInvalid(t *testing.T) {
+	type Holder struct {
+		Shape Shape
+	}
+
+	var decoded Holder
+	err := Unmarshal([]byte{2}, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid interface presence byte: 2")
+}
+
+// errorHolder has an optional error field, which stays nil when there is no error
+type errorHolder struct {
+	Status uint16
+	Err    error
+}
+
+func TestNilErrorField(t *testing.T) {
+	data, err := Marshal(errorHolder{Status: 200})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{200, 0, 0}, data)
+
+	var decoded errorHolder
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Nil(t, decoded.Err)
+
+	// A non-nil error needs a registered concrete type
+	_, err = Marshal(errorHolder{Status: 500, Err: errors.New("boom")})
+	assert.ErrorContains(t, err, "is not registered")
+}
@@ -0,0 +1,63 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sparseRecord struct {
+	Name   string   `binary:"omitempty"`
+	Count  uint32   `binary:"omitempty"`
+	Values []uint16 `binary:"omitempty"`
+	Code   string   `binary:"omitempty,4"`
+	Always uint8
+}
+
+func TestOmitEmptyZeroValues(t *testing.T) {
+	data, err := Marshal(sparseRecord{Always: 9})
+	assert.NoError(t, err)
+	// One presence byte per omitted field
+	assert.Equal(t, []byte{0, 0, 0, 0, 9}, data)
+
+	decoded := sparseRecord{Name: "stale", Count: 5, Values: []uint16{1}, Code: "x"}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, sparseRecord{Always: 9}, decoded)
+}
+
+func TestOmitEmptyNonZeroValues(t *testing.T) {
+	original := sparseRecord{Name: "ab", Count: 7, Values: []uint16{3, 4}, Code: "XY", Always: 1}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		1, 2, 0, 0, 0, 'a', 'b',
+		1, 7, 0, 0, 0,
+		1, 2, 0, 0, 0, 3, 0, 4, 0,
+		1, 'X', 'Y', 0, 0,
+		1,
+	}, data)
+
+	var decoded sparseRecord
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestOmitEmptyEmptySlice(t *testing.T) {
+	// Only nil is the zero value of a slice; an empty slice is present with a count of 0
+	data, err := Marshal(sparseRecord{Values: []uint16{}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 1, 0, 0, 0, 0, 0, 0}, data)
+
+	var decoded sparseRecord
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.NotNil(t, decoded.Values)
+	assert.Empty(t, decoded.Values)
+}
+
+func TestOmitEmptyInvalidPresence(t *testing.T) {
+	var decoded sparseRecord
+	err := Unmarshal([]byte{2, 0, 0, 0, 0}, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid presence byte")
+}
@@ -0,0 +1,97 @@
+package binary
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// orderPrefix starts the tag option that sets the position of a field in the
+// encoding, e.g. `binary:"order:2"`
+const orderPrefix = "order:"
+
+// fieldOrders caches the result of fieldOrder, keyed by reflect.Type
+var fieldOrders sync.Map
+
+// fieldOrder returns the indexes of the fields of a struct type in the order
+// they are encoded. A field tagged "order:N" sorts as if it were declared at
+// position N and other fields keep their declaration index. At the same
+// position a tagged field comes first, and remaining ties are broken by
+// declaration order. Without order tags this is the declaration order.
+// Malformed order options are ignored here and reported by withoutOrderOption.
+func fieldOrder(typ reflect.Type) []int {
+	if cached, ok := fieldOrders.Load(typ); ok {
+		return cached.([]int)
+	}
+
+	indexes := make([]int, typ.NumField())
+	keys := make([]int, typ.NumField())
+	tagged := make([]bool, typ.NumField())
+	for i := range indexes {
+		indexes[i], keys[i] = i, i
+		if position, ok, err := orderOption(typ.Field(i).Tag.Get("binary")); ok && err == nil {
+			keys[i], tagged[i] = position, true
+		}
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		a, b = indexes[a], indexes[b]
+		if keys[a] != keys[b] {
+			return keys[a] < keys[b]
+		}
+		return tagged[a] && !tagged[b]
+	})
+
+	fieldOrders.Store(typ, indexes)
+	return indexes
+}
+
+// orderOption returns the position set by an "order:N" option of a tag
+func orderOption(tag string) (int, bool, error) {
+	if !strings.Contains(tag, orderPrefix) {
+		return 0, false, nil
+	}
+	for _, option := range strings.Split(tag, ",") {
+		if value, ok := strings.CutPrefix(option, orderPrefix); ok {
+			position, err := strconv.Atoi(value)
+			if err != nil || position < 0 {
+				return 0, false, fmt.Errorf("%w: %s", ErrInvalidTag, option)
+			}
+			return position, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// withoutOrderOption returns a tag without its "order:N" option, which the
+// struct field loops have already applied through fieldOrder
+func withoutOrderOption(tag string) (string, error) {
+	if _, _, err := orderOption(tag); err != nil {
+		return tag, err
+	}
+	if !strings.Contains(tag, orderPrefix) {
+		return tag, nil
+	}
+	var options []string
+	for _, option := range strings.Split(tag, ",") {
+		if !strings.HasPrefix(option, orderPrefix) {
+			options = append(options, option)
+		}
+	}
+	return strings.Join(options, ","), nil
+}
+
+// encodedBefore reports whether field a of a struct type is encoded before field b
+func encodedBefore(typ reflect.Type, a, b int) bool {
+	for _, i := range fieldOrder(typ) {
+		switch i {
+		case a:
+			return true
+		case b:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderTag(t *testing.T) {
+	type Header struct {
+		Flags uint8  `binary:"order:1"`
+		ID    uint32 `binary:"order:0"`
+	}
+
+	original := Header{Flags: 0xAA, ID: 7}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// ID is written first, against declaration order
+	assert.Equal(t, []byte{7, 0, 0, 0, 0xAA}, data)
+
+	var decoded Header
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestOrderTagWithUntaggedFields(t *testing.T) {
+	type Record struct {
+		A uint8
+		B uint8
+		C uint8 `binary:"order:0"`
+		D string
+		E uint8 `binary:"order:3,omitempty"`
+	}
+
+	// Untagged fields sort by their declaration index and keep their relative
+	// order; a tagged field sorts before an untagged one at the same position
+	original := Record{A: 1, B: 2, C: 3, D: "d", E: 5}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{3, 1, 2, 1, 5, 1, 0, 0, 0, 'd'}, data)
+
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+
+	layout, err := Layout(original)
+	assert.NoError(t, err)
+	assert.Equal(t, "C", layout[0].Name)
+	assert.Equal(t, "D", layout[4].Name)
+}
+
+func TestOrderTagWithLengthReference(t *testing.T) {
+	type Message struct {
+		Items []uint16 `binary:"lenfrom:Count"`
+		Count uint8    `binary:"order:0"`
+		Rest  []byte   `binary:"rest,order:9"`
+		Kind  uint8
+	}
+
+	original := Message{Items: []uint16{1, 2}, Count: 2, Rest: []byte{9}, Kind: 4}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2, 1, 0, 2, 0, 4, 9}, data)
+
+	var decoded Message
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestOrderTagInvalid(t *testing.T) {
+	type Bad struct {
+		A uint8 `binary:"order:x"`
+	}
+	_, err := Marshal(Bad{})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	assert.ErrorIs(t, Unmarshal([]byte{1}, &Bad{}), ErrInvalidTag)
+
+	type Negative struct {
+		A uint8 `binary:"order:-1"`
+	}
+	_, err = Marshal(Negative{})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
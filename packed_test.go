@@ -0,0 +1,70 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackedBools(t *testing.T) {
+	type Flags struct {
+		Values []bool `binary:"packed"`
+	}
+
+	for _, n := range []int{0, 1, 7, 8, 9, 16, 1000} {
+		original := Flags{Values: make([]bool, n)}
+		for i := range original.Values {
+			original.Values[i] = i%3 == 0
+		}
+
+		data, err := Marshal(original)
+		assert.NoError(t, err)
+		assert.Len(t, data, 4+(n+7)/8, "length %d", n)
+
+		var decoded Flags
+		assert.NoError(t, Unmarshal(data, &decoded))
+		assert.Equal(t, original.Values, decoded.Values, "length %d", n)
+	}
+}
+
+func TestPackedBoolsLayout(t *testing.T) {
+	type Flags struct {
+		Values []bool `binary:"packed,prefix:1"`
+		Tail   uint8
+	}
+
+	original := Flags{
+		Values: []bool{true, false, true, true, false, false, false, false, false, true},
+		Tail:   7,
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// Least significant bit first, unused bits of the last byte are zero
+	assert.Equal(t, []byte{10, 0b00001101, 0b00000010, 7}, data)
+
+	decoded := Flags{Values: make([]bool, 0, 16)}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestPackedBoolsInvalid(t *testing.T) {
+	type NotBool struct {
+		Values []uint8 `binary:"packed"`
+	}
+	_, err := Marshal(NotBool{Values: []uint8{1}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "packed tag requires a []bool field")
+
+	type Fixed struct {
+		Values []bool `binary:"packed,8"`
+	}
+	_, err = Marshal(Fixed{})
+	assert.Error(t, err)
+
+	// The declared count needs more bytes than the input holds
+	type Flags struct {
+		Values []bool `binary:"packed"`
+	}
+	var decoded Flags
+	assert.Error(t, Unmarshal([]byte{17, 0, 0, 0, 0xff, 0xff}, &decoded))
+}
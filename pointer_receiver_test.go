@@ -0,0 +1,116 @@
+package binary
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pointerVersion implements both methods on a pointer receiver only
+type pointerVersion struct {
+	Major, Minor uint8
+}
+
+func (v *pointerVersion) MarshalBinary() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d", v.Major, v.Minor)), nil
+}
+
+func (v *pointerVersion) UnmarshalBinary(data []byte) error {
+	_, err := fmt.Sscanf(string(data), "%d.%d", &v.Major, &v.Minor)
+	return err
+}
+
+// pointerCode is a non-struct type with pointer receiver methods
+type pointerCode uint16
+
+func (c *pointerCode) MarshalBinary() ([]byte, error) {
+	return []byte(fmt.Sprintf("#%d", *c)), nil
+}
+
+func (c *pointerCode) UnmarshalBinary(data []byte) error {
+	_, err := fmt.Sscanf(string(data), "#%d", (*uint16)(c))
+	return err
+}
+
+func TestPointerReceiverMarshalerOnValueField(t *testing.T) {
+	type Release struct {
+		Version pointerVersion
+		Code    pointerCode
+		Build   uint16
+	}
+
+	original := Release{Version: pointerVersion{Major: 1, Minor: 12}, Code: 42, Build: 7}
+	want := binary.LittleEndian.AppendUint32(nil, 4)
+	want = append(want, "1.12"...)
+	want = binary.LittleEndian.AppendUint32(want, 3)
+	want = append(want, "#42"...)
+	want = append(want, 7, 0)
+
+	// The field is addressable when marshaling through a pointer and is
+	// copied when marshaling a value; both use the custom marshaler
+	data, err := Marshal(&original)
+	assert.NoError(t, err)
+	assert.Equal(t, want, data)
+
+	data, err = Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, want, data)
+
+	size, err := Size(original)
+	assert.NoError(t, err)
+	assert.Equal(t, len(want), size)
+
+	var decoded Release
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestPointerReceiverMarshalerInNestedStruct(t *testing.T) {
+	type Component struct {
+		Name    string
+		Version pointerVersion
+	}
+	type Manifest struct {
+		Components []Component
+	}
+
+	original := Manifest{Components: []Component{
+		{Name: "core", Version: pointerVersion{Major: 2, Minor: 0}},
+		{Name: "ui", Version: pointerVersion{Major: 0, Minor: 9}},
+	}}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Manifest
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+// selfRecorder remembers the address it was decoded into
+type selfRecorder struct {
+	self *selfRecorder
+}
+
+func (r *selfRecorder) MarshalBinary() ([]byte, error) {
+	return nil, nil
+}
+
+func (r *selfRecorder) UnmarshalBinary(data []byte) error {
+	r.self = r
+	return nil
+}
+
+func TestPointerReceiverUnmarshalerDecodesInPlace(t *testing.T) {
+	type Holder struct {
+		Block    [1 << 16]byte
+		Recorder selfRecorder
+	}
+
+	// The field is decoded through its address rather than through a copy
+	data := MustMarshal(&Holder{})
+	var decoded Holder
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Same(t, &decoded.Recorder, decoded.Recorder.self)
+}
@@ -0,0 +1,169 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNilPointerPresenceFlag(t *testing.T) {
+	type Optional struct {
+		A *uint32
+		B *uint32
+		C uint8
+	}
+
+	value := uint32(0x01020304)
+	original := Optional{A: nil, B: &value, C: 9}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0,                         // A is nil
+		1, 0x04, 0x03, 0x02, 0x01, // B is present
+		9,
+	}, data)
+
+	// Stale values in the destination are replaced, including resetting pointers to nil
+	stale := uint32(7)
+	decoded := Optional{A: &stale}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Nil(t, decoded.A)
+	assert.NotNil(t, decoded.B)
+	assert.Equal(t, value, *decoded.B)
+	assert.Equal(t, uint8(9), decoded.C)
+}
+
+func TestPointerToStructAndTags(t *testing.T) {
+	type Inner struct {
+		Name string `binary:"4"`
+	}
+	type Outer struct {
+		Inner  *Inner
+		Code   *string `binary:"3"`
+		Absent *Inner
+	}
+
+	code := "abc"
+	original := Outer{Inner: &Inner{Name: "in"}, Code: &code}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Len(t, data, 1+4+1+3+1)
+
+	var decoded Outer
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestTopLevelPointerIsTransparent(t *testing.T) {
+	value := uint16(5)
+	ptr := &value
+
+	direct, err := Marshal(value)
+	assert.NoError(t, err)
+	viaPointer, err := Marshal(ptr)
+	assert.NoError(t, err)
+	viaDoublePointer, err := Marshal(&ptr)
+	assert.NoError(t, err)
+	assert.Equal(t, direct, viaPointer)
+	assert.Equal(t, direct, viaDoublePointer)
+
+	var decoded *uint16
+	assert.NoError(t, Unmarshal(direct, &decoded))
+	assert.Equal(t, value, *decoded)
+
+	var nilPtr *uint16
+	_, err = Marshal(nilPtr)
+	assert.Error(t, err)
+}
+
+func TestInvalidPresenceByte(t *testing.T) {
+	type Optional struct {
+		A *uint8
+	}
+
+	var decoded Optional
+	assert.Error(t, Unmarshal([]byte{2, 1}, &decoded))
+	assert.Error(t, Unmarshal([]byte{}, &decoded))
+}
+
+func TestPointerToCustomUnmarshaler(t *testing.T) {
+	type Holder struct {
+		Custom *CustomType
+		Code   *pointerCode
+		Items  []*CustomType
+		After  uint8
+	}
+
+	code := pointerCode(5)
+	original := Holder{
+		Custom: &CustomType{Value: "x"},
+		Code:   &code,
+		Items:  []*CustomType{{Value: "y"}},
+		After:  9,
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// A presence byte precedes length + data, like pointer elements of slices
+	assert.Equal(t, []byte{1, 8, 0, 0, 0, 'c', 'u', 's', 't', 'o', 'm', ':', 'x'}, data[:13])
+	assert.Equal(t, []byte{1, 2, 0, 0, 0, '#', '5'}, data[13:20])
+	assert.Equal(t, []byte{1, 0, 0, 0, 1, 8, 0, 0, 0}, data[20:29])
+
+	var decoded Holder
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+
+	// Nil pointers are written as a single zero byte and decode as nil
+	data, err = Marshal(Holder{After: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 3}, data)
+
+	decoded = Holder{Custom: &CustomType{Value: "stale"}}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Nil(t, decoded.Custom)
+	assert.Nil(t, decoded.Code)
+	assert.Equal(t, uint8(3), decoded.After)
+}
+
+func TestSliceOfPointers(t *testing.T) {
+	type Item struct {
+		ID   uint16
+		Name string
+	}
+	type Cart struct {
+		Items []*Item
+		Fixed []*Item `binary:"3"`
+		ByKey map[string]*Item
+	}
+
+	a, b := Item{ID: 1, Name: "a"}, Item{ID: 2, Name: "b"}
+	original := Cart{
+		Items: []*Item{&a, nil, &b},
+		Fixed: []*Item{&a},
+		ByKey: map[string]*Item{"none": nil, "b": &b},
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// Each element has its own presence byte
+	assert.Equal(t, []byte{
+		3, 0, 0, 0,
+		1, 1, 0, 1, 0, 0, 0, 'a',
+		0,
+		1, 2, 0, 1, 0, 0, 0, 'b',
+	}, data[:21])
+
+	var decoded Cart
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, []*Item{&a, nil, &b}, decoded.Items)
+	// The fixed count pads with nil pointers
+	assert.Equal(t, []*Item{&a, nil, nil}, decoded.Fixed)
+	assert.Equal(t, original.ByKey, decoded.ByKey)
+	// Decoded elements are new values, not the originals
+	assert.NotSame(t, &a, decoded.Items[0])
+
+	// Stale elements of a reused destination are replaced, including by nil
+	stale := Cart{Items: []*Item{{ID: 9}, {ID: 8}, {ID: 7}}}
+	assert.NoError(t, Unmarshal(data, &stale))
+	assert.Equal(t, []*Item{&a, nil, &b}, stale.Items)
+}
@@ -0,0 +1,101 @@
+package binary
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// marshalFresh encodes v like Marshal did before buffers were pooled
+func marshalFresh(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeValue(v, &encodeState{w: &buf}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func TestMarshalResultDoesNotAliasPool(t *testing.T) {
+	first, err := Marshal("first value")
+	assert.NoError(t, err)
+	want := append([]byte(nil), first...)
+
+	// Later calls reuse the pooled buffer and must not overwrite earlier results
+	for i := 0; i < 10; i++ {
+		_, err := Marshal(strings.Repeat("x", 20))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, want, first)
+
+	// Results are right-sized copies, so appending to them cannot reach the pool either
+	assert.Equal(t, len(first), cap(first))
+
+	// Values that encode to nothing still yield nil
+	data, err := Marshal(struct{}{})
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestMarshalPoolConcurrent(t *testing.T) {
+	const goroutines = 32
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Each goroutine uses its own size so that reused buffers would show
+			value := strings.Repeat(string(rune('a'+i%26)), 10+i*37)
+			want, err := marshalFresh(value)
+			if err != nil {
+				errs <- err
+				return
+			}
+			var results [][]byte
+			for j := 0; j < 100; j++ {
+				data, err := Marshal(value)
+				if err != nil {
+					errs <- err
+					return
+				}
+				results = append(results, data)
+			}
+			for j, data := range results {
+				if !bytes.Equal(want, data) {
+					errs <- fmt.Errorf("goroutine %d: result %d was modified", i, j)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestMarshalPoolReducesAllocations(t *testing.T) {
+	person := examplePerson()
+	fresh := testing.AllocsPerRun(100, func() {
+		if _, err := marshalFresh(person); err != nil {
+			panic(err)
+		}
+	})
+	assert.Less(t, allocsPerMarshal(person), fresh)
+}
+
+func BenchmarkMarshalPersonFreshBuffer(b *testing.B) {
+	person := examplePerson()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := marshalFresh(person); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixBigEndianTag(t *testing.T) {
+	type Frame struct {
+		Name  string   `binary:"prefixbe"`
+		Data  []byte   `binary:"prefixbe"`
+		Items []uint16 `binary:"prefixbe"`
+		Plain string
+	}
+
+	original := Frame{
+		Name:  "ab",
+		Data:  []byte{0xCC},
+		Items: []uint16{0x0102},
+		Plain: "z",
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x00, 0x00, 0x00, 0x02, 'a', 'b', // big-endian prefix
+		0x00, 0x00, 0x00, 0x01, 0xCC, // big-endian prefix
+		0x00, 0x00, 0x00, 0x01, 0x02, 0x01, // big-endian prefix, little-endian element
+		0x01, 0x00, 0x00, 0x00, 'z', // default little-endian prefix
+	}, data)
+
+	var decoded Frame
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestPrefixBigEndianTagNested(t *testing.T) {
+	type Frame struct {
+		Words  []string  `binary:"prefixbe"`
+		Matrix [][]uint8 `binary:"prefixbe"`
+		Pairs  [2]string `binary:"prefixbe"`
+	}
+
+	original := Frame{
+		Words:  []string{"x"},
+		Matrix: [][]uint8{{7}},
+		Pairs:  [2]string{"p", "q"},
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 'x',
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 7,
+		0x00, 0x00, 0x00, 0x01, 'p', 0x00, 0x00, 0x00, 0x01, 'q',
+	}, data)
+
+	var decoded Frame
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
@@ -0,0 +1,123 @@
+package binary
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixWidthTag(t *testing.T) {
+	type Message struct {
+		Short string           `binary:"prefix:1"`
+		Data  []byte           `binary:"prefix:2"`
+		Items []uint16         `binary:"prefix:8"`
+		Names []string         `binary:"prefix:1"`
+		Attrs map[string]uint8 `binary:"prefix:2,prefixbe"`
+		Plain string
+	}
+
+	original := Message{
+		Short: "hi",
+		Data:  []byte{1, 2, 3},
+		Items: []uint16{7},
+		Names: []string{"a", "bc"},
+		Attrs: map[string]uint8{"k": 9},
+		Plain: "p",
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		2, 'h', 'i',
+		3, 0, 1, 2, 3,
+		1, 0, 0, 0, 0, 0, 0, 0, 7, 0,
+		2, 1, 'a', 2, 'b', 'c', // element prefixes use the same width
+		0, 1, 0, 1, 'k', 9, // big-endian 2-byte prefixes
+		1, 0, 0, 0, 'p',
+	}, data)
+
+	var decoded Message
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestPrefixWidthOverflow(t *testing.T) {
+	type Message struct {
+		Name string `binary:"prefix:1"`
+	}
+
+	_, err := Marshal(Message{Name: strings.Repeat("x", 255)})
+	assert.NoError(t, err)
+
+	_, err = Marshal(Message{Name: strings.Repeat("x", 256)})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not fit in a 1-byte length prefix")
+
+	type Items struct {
+		Values []uint8 `binary:"prefix:2"`
+	}
+	_, err = Marshal(Items{Values: make([]uint8, 1<<16)})
+	assert.Error(t, err)
+}
+
+func TestPrefixWidthInvalid(t *testing.T) {
+	type Message struct {
+		Name string `binary:"prefix:3"`
+	}
+
+	_, err := Marshal(Message{Name: "x"})
+	assert.Error(t, err)
+
+	var decoded Message
+	err = Unmarshal([]byte{1, 0, 0, 'x'}, &decoded)
+	assert.Error(t, err)
+}
+
+func TestPrefixWidthMismatchOnDecode(t *testing.T) {
+	type Narrow struct {
+		Name   string   `binary:"prefix:2"`
+		Data   []byte   `binary:"prefix:2"`
+		Values []uint16 `binary:"prefix:2"`
+	}
+	type Wide struct {
+		Name   string
+		Data   []byte
+		Values []uint16
+	}
+
+	original := Narrow{Name: "hi", Data: []byte{1, 2}, Values: []uint16{1, 2}}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Narrow
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+
+	// Each field decoded with the default 4-byte prefix reads a length that
+	// exceeds the data, which is reported instead of yielding corrupt values
+	str, err := Marshal(struct {
+		Name string `binary:"prefix:2"`
+	}{Name: "hi"})
+	assert.NoError(t, err)
+	var wideStr struct{ Name string }
+	assert.ErrorIs(t, Unmarshal(str, &wideStr), io.ErrUnexpectedEOF)
+
+	byteData, err := Marshal(struct {
+		Data []byte `binary:"prefix:2"`
+	}{Data: []byte{1, 2}})
+	assert.NoError(t, err)
+	var wideBytes struct{ Data []byte }
+	assert.ErrorIs(t, Unmarshal(byteData, &wideBytes), io.ErrUnexpectedEOF)
+
+	values, err := Marshal(struct {
+		Values []uint16 `binary:"prefix:2"`
+	}{Values: []uint16{1, 2}})
+	assert.NoError(t, err)
+	var wideValues struct{ Values []uint16 }
+	assert.Error(t, Unmarshal(values, &wideValues))
+
+	var wide Wide
+	assert.Error(t, Unmarshal(data, &wide))
+}
@@ -0,0 +1,101 @@
+package binary
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreserveNilSlices(t *testing.T) {
+	type Record struct {
+		Tags   []string
+		Data   []byte
+		Scores []uint32
+		Attrs  map[string]int8
+		Nested [][]int16
+	}
+
+	codec := NewCodec().WithPreserveNil(true)
+
+	// Nil values decode as nil
+	var decoded Record
+	data, err := codec.Marshal(Record{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0}, data)
+	assert.NoError(t, codec.Unmarshal(data, &decoded))
+	assert.Nil(t, decoded.Tags)
+	assert.Nil(t, decoded.Data)
+	assert.Nil(t, decoded.Scores)
+	assert.Nil(t, decoded.Attrs)
+	assert.Nil(t, decoded.Nested)
+
+	// Empty values decode as empty but non-nil
+	empty := Record{
+		Tags:   []string{},
+		Data:   []byte{},
+		Scores: []uint32{},
+		Attrs:  map[string]int8{},
+		Nested: [][]int16{nil, {}},
+	}
+	data, err = codec.Marshal(empty)
+	assert.NoError(t, err)
+	decoded = Record{}
+	assert.NoError(t, codec.Unmarshal(data, &decoded))
+	assert.Equal(t, empty, decoded)
+	assert.NotNil(t, decoded.Tags)
+	assert.NotNil(t, decoded.Data)
+	assert.NotNil(t, decoded.Scores)
+	assert.NotNil(t, decoded.Attrs)
+	// Elements keep the distinction too
+	assert.Nil(t, decoded.Nested[0])
+	assert.NotNil(t, decoded.Nested[1])
+
+	// A nil value replaces existing data in the destination
+	decoded = Record{Tags: []string{"old"}, Attrs: map[string]int8{"k": 1}}
+	data, err = codec.Marshal(Record{Scores: []uint32{1, 2}})
+	assert.NoError(t, err)
+	assert.NoError(t, codec.Unmarshal(data, &decoded))
+	assert.Equal(t, Record{Scores: []uint32{1, 2}}, decoded)
+}
+
+func TestPreserveNilTopLevel(t *testing.T) {
+	codec := NewCodec().WithPreserveNil(true)
+
+	data, err := codec.Marshal([]int32(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0}, data)
+	decoded := []int32{1}
+	assert.NoError(t, codec.UnmarshalReader(bytes.NewReader(data), &decoded))
+	assert.Nil(t, decoded)
+
+	data, err = codec.Marshal([]int32{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 0, 0, 0, 0}, data)
+	assert.NoError(t, codec.Unmarshal(data, &decoded))
+	assert.Equal(t, []int32{}, decoded)
+
+	var bad []int32
+	assert.Error(t, codec.Unmarshal([]byte{2, 0, 0, 0, 0}, &bad))
+}
+
+func TestPreserveNilKeepsBuiltinFraming(t *testing.T) {
+	type Host struct {
+		Addr net.IP
+		Tail Raw
+	}
+
+	codec := NewCodec().WithPreserveNil(true)
+	original := Host{Addr: net.IPv4(10, 0, 0, 1).To4(), Tail: Raw{9}}
+	data, err := codec.Marshal(original)
+	assert.NoError(t, err)
+	expected, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, data)
+
+	// Without the option nil and empty slices are not told apart
+	plain, err := Marshal([]int32(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0}, plain)
+}
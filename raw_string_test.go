@@ -0,0 +1,43 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawStringKeepsTrailingNULs(t *testing.T) {
+	type Record struct {
+		Key     string `binary:"raw:16"`
+		Trimmed string `binary:"16"`
+	}
+
+	value := "key\x00\x00\x01\x02\x00\x00\x00\x00\x00\x00\x00\x00\x00"
+	assert.Len(t, value, 16)
+	original := Record{Key: value, Trimmed: value}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Len(t, data, 32)
+	assert.Equal(t, data[:16], data[16:])
+
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, value, decoded.Key)
+	assert.Equal(t, "key\x00\x00\x01\x02", decoded.Trimmed)
+}
+
+func TestRawStringPadding(t *testing.T) {
+	type Record struct {
+		Key string `binary:"raw:4"`
+	}
+
+	// Short values are still zero-padded, and the padding is part of the decoded value
+	data, err := Marshal(Record{Key: "ab"})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{'a', 'b', 0, 0}, data)
+
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, "ab\x00\x00", decoded.Key)
+}
@@ -0,0 +1,144 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawTrailingField(t *testing.T) {
+	type Inner struct {
+		ID   uint16
+		Name string
+	}
+	type Envelope struct {
+		Kind    uint8
+		Payload Raw
+	}
+
+	inner := Inner{ID: 7, Name: "ab"}
+	payload, err := Marshal(inner)
+	assert.NoError(t, err)
+
+	data, err := Marshal(Envelope{Kind: 3, Payload: payload})
+	assert.NoError(t, err)
+	// The pre-encoded bytes are embedded verbatim, without a length prefix
+	assert.Equal(t, append([]byte{3}, payload...), data)
+
+	var decoded Envelope
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, uint8(3), decoded.Kind)
+	assert.Equal(t, Raw(payload), decoded.Payload)
+
+	var decodedInner Inner
+	assert.NoError(t, Unmarshal(decoded.Payload, &decodedInner))
+	assert.Equal(t, inner, decodedInner)
+}
+
+func TestRawFixedLength(t *testing.T) {
+	type Frame struct {
+		Header Raw `binary:"4"`
+		Body   string
+	}
+
+	original := Frame{Header: Raw{1, 2, 3, 4}, Body: "x"}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4, 1, 0, 0, 0, 'x'}, data)
+
+	var decoded Frame
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestRawTopLevel(t *testing.T) {
+	payload, err := Marshal(uint32(5))
+	assert.NoError(t, err)
+
+	data, err := Marshal(Raw(payload))
+	assert.NoError(t, err)
+	assert.Equal(t, payload, data)
+
+	var decoded Raw
+	assert.NoError(t, Unmarshal([]byte{9, 8, 7}, &decoded))
+	assert.Equal(t, Raw{9, 8, 7}, decoded)
+}
+
+func TestRawWithoutKnownLength(t *testing.T) {
+	type NotLast struct {
+		Payload Raw
+		Tail    uint8
+	}
+	_, err := Marshal(NotLast{Payload: Raw{1}})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	assert.ErrorIs(t, Unmarshal([]byte{1, 2}, &NotLast{}), ErrInvalidTag)
+
+	_, err = Marshal([]Raw{{1}, {2}})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	var list []Raw
+	assert.ErrorIs(t, Unmarshal([]byte{1, 0, 0, 0, 1}, &list), ErrInvalidTag)
+
+	type Tagged struct {
+		Payload Raw `binary:"prefix:2"`
+	}
+	_, err = Marshal(Tagged{})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
+
+func TestRawOnlyAtTopLevel(t *testing.T) {
+	type Frame struct {
+		Kind    uint8
+		Payload Raw
+	}
+
+	// A nested Raw would swallow the fields that follow its struct
+	type Nested struct {
+		Frame Frame
+		Tail  uint8
+	}
+	_, err := Marshal(Nested{Frame: Frame{Payload: Raw{1}}, Tail: 2})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	assert.ErrorIs(t, Unmarshal([]byte{1, 1, 2}, &Nested{}), ErrInvalidTag)
+
+	// A slice element is rejected even when the slice is the last field
+	type List struct {
+		Frames []Frame
+	}
+	_, err = Marshal(List{Frames: []Frame{{Kind: 1}, {Kind: 2}}})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	assert.ErrorIs(t, Unmarshal([]byte{2, 0, 0, 0, 1, 2}, &List{}), ErrInvalidTag)
+
+	// With a fixed length a nested Raw is fine
+	type Sized struct {
+		Payload Raw `binary:"2"`
+	}
+	type Outer struct {
+		Inner []Sized
+		Tail  uint8
+	}
+	original := Outer{Inner: []Sized{{Raw{1, 2}}}, Tail: 3}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	var decoded Outer
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestRawWithFieldOrder(t *testing.T) {
+	// With "order:N" the Raw field is encoded last, after the ignored field
+	// that follows it in the ordered field list
+	type Frame struct {
+		Payload Raw `binary:"order:5"`
+		Kind    uint8
+		Note    string `binary:"-"`
+	}
+
+	original := Frame{Payload: Raw{7, 8}, Kind: 2}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2, 7, 8}, data)
+
+	var decoded Frame
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
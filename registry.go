@@ -0,0 +1,231 @@
+package binary
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// typeRegistry maps the type codes written for interface values to concrete types
+var typeRegistry = struct {
+	sync.RWMutex
+	types map[uint32]reflect.Type
+	ids   map[reflect.Type]uint32
+}{
+	types: make(map[uint32]reflect.Type),
+	ids:   make(map[reflect.Type]uint32),
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*BinaryMarshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*BinaryUnmarshaler)(nil)).Elem()
+)
+
+// RegisterType associates a type code with the concrete type of example, so that
+// values of that type can be stored in interface-typed fields. A non-nil interface
+// value is encoded as a presence byte of 1, its type code (uint32) and the encoding
+// of the concrete value, and decoding uses the code to allocate a value of the
+// registered type. A nil interface is encoded as a single presence byte of 0.
+//
+// Register each type once, typically from an init function, with the same code in
+// every program that exchanges data. RegisterType panics if the id or the type is
+// already registered with a different counterpart.
+func RegisterType(id uint32, example interface{}) {
+	if example == nil {
+		panic("binary: RegisterType called with nil example")
+	}
+	typ := reflect.TypeOf(example)
+
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+	if existing, ok := typeRegistry.types[id]; ok && existing != typ {
+		panic(fmt.Sprintf("binary: type code %d registered for both %s and %s", id, existing, typ))
+	}
+	if existing, ok := typeRegistry.ids[typ]; ok && existing != id {
+		panic(fmt.Sprintf("binary: type %s registered with both code %d and %d", typ, existing, id))
+	}
+	typeRegistry.types[id] = typ
+	typeRegistry.ids[typ] = id
+}
+
+// registeredID returns the type code registered for typ
+func registeredID(typ reflect.Type) (uint32, bool) {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+	id, ok := typeRegistry.ids[typ]
+	return id, ok
+}
+
+// registeredType returns the type registered for a type code
+func registeredType(id uint32) (reflect.Type, bool) {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+	typ, ok := typeRegistry.types[id]
+	return typ, ok
+}
+
+// usesCustomCodec reports whether values of a registered type stored in an
+// interface are encoded with their BinaryMarshaler/BinaryUnmarshaler methods.
+// Both methods must be available through a pointer to the value, so that the
+// encoder and decoder always agree on the format.
+func usesCustomCodec(typ reflect.Type) bool {
+	ptr := typ
+	if typ.Kind() != reflect.Ptr {
+		ptr = reflect.PointerTo(typ)
+	}
+	return !isBuiltinType(ptr.Elem()) && ptr.Implements(marshalerType) && ptr.Implements(unmarshalerType)
+}
+
+// encodeInterface serializes an interface like a pointer, as a presence byte
+// (0 = nil, 1 = present) followed by the registered type code and the value
+func encodeInterface(field reflect.Value, buf *encodeState, tag string) error {
+	buf.containers++
+	defer func() { buf.containers-- }()
+
+	if field.IsNil() {
+		_, err := buf.Write([]byte{0})
+		return err
+	}
+	value := field.Elem()
+	typ := value.Type()
+	id, ok := registeredID(typ)
+	if !ok {
+		return fmt.Errorf("type %s is not registered", typ)
+	}
+	if _, err := buf.Write([]byte{1}); err != nil {
+		return err
+	}
+	if err := binary.Write(buf, buf.byteOrder(), id); err != nil {
+		return err
+	}
+
+	// Custom marshalers are written as length + data, like struct fields
+	if usesCustomCodec(typ) {
+		return encodeCustom(value, buf)
+	}
+	return encodeField(value, buf, tag)
+}
+
+// decodeInterface deserializes a value written by encodeInterface into an interface field
+func decodeInterface(buf *decodeState, field reflect.Value, tag string) error {
+	if err := buf.enter(); err != nil {
+		return err
+	}
+	defer buf.leave()
+
+	present, err := buf.ReadByte()
+	if err != nil {
+		return err
+	}
+	switch present {
+	case 0:
+		field.SetZero()
+		return nil
+	case 1:
+	default:
+		return fmt.Errorf("invalid interface presence byte: %d", present)
+	}
+
+	var id uint32
+	if err := binary.Read(buf, buf.byteOrder(), &id); err != nil {
+		return err
+	}
+	typ, ok := registeredType(id)
+	if !ok {
+		return fmt.Errorf("unknown type code %d", id)
+	}
+	if !typ.Implements(field.Type()) {
+		return fmt.Errorf("registered type %s does not implement %s", typ, field.Type())
+	}
+
+	if usesCustomCodec(typ) {
+		return decodeCustom(buf, field, typ)
+	}
+	value := reflect.New(typ).Elem()
+	if err := decodeField(buf, value, tag); err != nil {
+		return err
+	}
+	field.Set(value)
+	return nil
+}
+
+// encodeCustom writes a value through its BinaryMarshaler as length + data, like struct fields
+func encodeCustom(value reflect.Value, buf *encodeState) error {
+	typ := value.Type()
+	ptr := value
+	if typ.Kind() != reflect.Ptr {
+		ptr = reflect.New(typ)
+		ptr.Elem().Set(value)
+	} else if value.IsNil() {
+		return fmt.Errorf("cannot encode nil %s", typ)
+	}
+	data, err := ptr.Interface().(BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := binary.Write(buf, buf.byteOrder(), uint32(len(data))); err != nil {
+		return err
+	}
+	_, err = buf.Write(data)
+	return err
+}
+
+// encodeCustomPointer writes a pointer to a custom marshaler as a presence byte
+// followed by the value as length + data, like pointer elements of slices
+func encodeCustomPointer(ptr reflect.Value, buf *encodeState) error {
+	if ptr.IsNil() {
+		_, err := buf.Write([]byte{0})
+		return err
+	}
+	if _, err := buf.Write([]byte{1}); err != nil {
+		return err
+	}
+	return encodeCustom(ptr, buf)
+}
+
+// decodeCustomPointer reads a pointer written by encodeCustomPointer into field,
+// allocating a new value for it
+func decodeCustomPointer(buf *decodeState, field reflect.Value) error {
+	present, err := buf.ReadByte()
+	if err != nil {
+		return err
+	}
+	switch present {
+	case 0:
+		field.SetZero()
+		return nil
+	case 1:
+		return decodeCustom(buf, field, field.Type())
+	default:
+		return fmt.Errorf("invalid pointer presence byte: %d", present)
+	}
+}
+
+// decodeCustom reads a value of type typ written by encodeCustom through its
+// BinaryUnmarshaler and stores it in field. The value is decoded into a new
+// variable, so field does not need to be addressable.
+func decodeCustom(buf *decodeState, field reflect.Value, typ reflect.Type) error {
+	value := reflect.New(typ).Elem()
+	ptr := value.Addr()
+	if typ.Kind() == reflect.Ptr {
+		value.Set(reflect.New(typ.Elem()))
+		ptr = value
+	}
+	var length uint32
+	if err := binary.Read(buf, buf.byteOrder(), &length); err != nil {
+		return err
+	}
+	if err := buf.checkLength(int(length)); err != nil {
+		return err
+	}
+	data, err := buf.readBytes(int(length))
+	if err != nil {
+		return err
+	}
+	if err := ptr.Interface().(BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		return err
+	}
+	field.Set(value)
+	return nil
+}
@@ -0,0 +1,97 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64
+}
+
+func (c Circle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type Rect struct {
+	Width, Height float64
+	Label         string
+}
+
+func (r *Rect) Area() float64 { return r.Width * r.Height }
+
+func init() {
+	RegisterType(100, Circle{})
+	RegisterType(101, &Rect{})
+	RegisterType(102, CustomType{})
+}
+
+func TestRegisteredInterfaceSlice(t *testing.T) {
+	type Drawing struct {
+		Shapes []Shape
+	}
+
+	original := Drawing{Shapes: []Shape{Circle{Radius: 2}, &Rect{Width: 3, Height: 4, Label: "r"}, Circle{Radius: 1}}}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	// Each element starts with a presence byte and its type code
+	assert.Equal(t, []byte{3, 0, 0, 0, 1, 100, 0, 0, 0}, data[:9])
+
+	var decoded Drawing
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+	assert.Equal(t, 12.0, decoded.Shapes[0].Area())
+	assert.Equal(t, 12.0, decoded.Shapes[1].Area())
+}
+
+func TestRegisteredEmptyInterfaceField(t *testing.T) {
+	type Message struct {
+		ID      uint16
+		Payload interface{}
+	}
+
+	// Registered types with custom marshalers keep their own encoding
+	original := Message{ID: 1, Payload: CustomType{Value: "x"}}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 0, 1, 102, 0, 0, 0, 8, 0, 0, 0, 'c', 'u', 's', 't', 'o', 'm', ':', 'x'}, data)
+
+	var decoded Message
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestInterfaceErrors(t *testing.T) {
+	type Holder struct {
+		Shape Shape
+	}
+
+	// Unregistered types cannot be encoded
+	type Square struct{ Circle }
+	_, err := Marshal(Holder{Shape: Square{}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is not registered")
+
+	// Unknown type codes and types not implementing the field's interface are rejected
+	var decoded Holder
+	err = Unmarshal([]byte{1, 99, 0, 0, 0}, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown type code 99")
+
+	err = Unmarshal([]byte{1, 102, 0, 0, 0, 0, 0, 0, 0}, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not implement")
+}
+
+func TestRegisterTypeConflicts(t *testing.T) {
+	// Registering the same pair again is allowed
+	assert.NotPanics(t, func() { RegisterType(100, Circle{}) })
+	assert.Panics(t, func() { RegisterType(100, Rect{}) })
+	assert.Panics(t, func() { RegisterType(103, Circle{}) })
+	assert.Panics(t, func() { RegisterType(104, nil) })
+}
@@ -0,0 +1,101 @@
+package binary
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// restTag marks a trailing []byte field that holds all remaining input bytes
+const restTag = "rest"
+
+// Raw holds bytes that are already encoded, such as a marshaled sub-message,
+// and is written verbatim without a length prefix. Since its length is not
+// recorded, a Raw value can only be decoded where its end is known:
+//   - as the top-level value or the last field of the top-level struct it
+//     takes all remaining input, like a field tagged "rest"
+//   - with a fixed-length tag such as `binary:"16"` it holds exactly that many
+//     bytes, padded with zeros or truncated when encoding, like a []byte
+//
+// Anywhere else, such as in a slice or map or with other tags, encoding and
+// decoding fail.
+type Raw []byte
+
+var rawType = reflect.TypeOf(Raw(nil))
+
+// isRestField reports whether a struct field takes all remaining input: a field
+// tagged "rest" or an untagged Raw field
+func isRestField(fieldType reflect.StructField, tag string) bool {
+	return tag == restTag || (fieldType.Type == rawType && tag == "")
+}
+
+// isLastField reports whether the field at index is the last field of typ that is
+// encoded for the given group. Like encodeStructFields, it skips unexported fields,
+// fields outside the group and fields tagged "-", which never reach the output.
+func isLastField(typ reflect.Type, index int, group string) bool {
+	order := fieldOrder(typ)
+	for i := len(order) - 1; i >= 0; i-- {
+		fieldType := typ.Field(order[i])
+		if !fieldType.IsExported() {
+			continue
+		}
+		included, tag := inGroup(fieldType.Tag.Get("binary"), group)
+		if !included {
+			continue
+		}
+		if tag, err := withoutOrderOption(tag); err == nil && tag == "-" {
+			continue
+		}
+		return order[i] == index
+	}
+	return false
+}
+
+// checkRestField validates a field tagged "rest": it must be a []byte and the
+// last encoded field of the top-level struct. trailing reports whether the
+// struct holding the field ends the top-level value; a rest field anywhere
+// else, such as in a nested struct or a slice element, could not be decoded.
+// group is the active field group, see isLastField.
+func checkRestField(val reflect.Value, index int, trailing bool, group string) error {
+	field := val.Field(index)
+	if field.Kind() != reflect.Slice || field.Type().Elem().Kind() != reflect.Uint8 {
+		return fmt.Errorf("%w: rest tag requires a []byte field, got %s", ErrInvalidTag, field.Type())
+	}
+	if !trailing || !isLastField(val.Type(), index, group) {
+		if field.Type() == rawType {
+			return fmt.Errorf("%w: a Raw field without a fixed length must be the last field of the top-level struct", ErrInvalidTag)
+		}
+		return fmt.Errorf("%w: rest tag is only allowed on the last field of the top-level struct", ErrInvalidTag)
+	}
+	return nil
+}
+
+// checkRawTag reports an error for a nested Raw value whose tag does not fix its length
+func checkRawTag(tag string) error {
+	if info, err := parseTag(tag); err == nil && !info.HasFixedLen {
+		return fmt.Errorf("%w: a Raw value needs a fixed length unless it is the last field of the top-level struct", ErrInvalidTag)
+	}
+	return nil
+}
+
+// encodeRest writes the bytes of a "rest" field without a length prefix
+func encodeRest(field reflect.Value, buf *encodeState) error {
+	_, err := buf.Write(field.Bytes())
+	return err
+}
+
+// decodeRest reads all remaining input into a "rest" field
+func decodeRest(buf *decodeState, field reflect.Value) error {
+	// Reaching the end of the input is expected here, so read from the
+	// underlying reader, which reports it as io.EOF
+	data, err := io.ReadAll(buf.Reader)
+	buf.n += int64(len(data))
+	if err != nil {
+		return err
+	}
+	if err := buf.checkCount(len(data)); err != nil {
+		return err
+	}
+	field.SetBytes(data)
+	return nil
+}
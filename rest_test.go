@@ -0,0 +1,123 @@
+package binary
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type framedPacket struct {
+	Type    uint8
+	Length  uint16
+	Trailer []byte `binary:"rest"`
+}
+
+func TestRestFieldDecodesTrailer(t *testing.T) {
+	data := []byte{2, 5, 0, 'h', 'e', 'l', 'l', 'o'}
+
+	var packet framedPacket
+	assert.NoError(t, Unmarshal(data, &packet))
+	assert.Equal(t, framedPacket{Type: 2, Length: 5, Trailer: []byte("hello")}, packet)
+
+	// The trailer is written without a length prefix
+	encoded, err := Marshal(packet)
+	assert.NoError(t, err)
+	assert.Equal(t, data, encoded)
+
+	// An empty trailer is valid
+	assert.NoError(t, Unmarshal([]byte{1, 0, 0}, &packet))
+	assert.Equal(t, uint8(1), packet.Type)
+	assert.Empty(t, packet.Trailer)
+}
+
+func TestRestFieldWithStream(t *testing.T) {
+	// On a stream the rest field reads until the end of the input
+	dec := NewDecoder(bytes.NewReader([]byte{3, 1, 0, 'x', 'y', 'z'}))
+	var packet framedPacket
+	assert.NoError(t, dec.Decode(&packet))
+	assert.Equal(t, []byte("xyz"), packet.Trailer)
+}
+
+func TestRestFieldValidation(t *testing.T) {
+	type NotLast struct {
+		Trailer []byte `binary:"rest"`
+		After   uint8
+	}
+	_, err := Marshal(NotLast{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "only allowed on the last field")
+	assert.Error(t, Unmarshal([]byte{1, 2}, &NotLast{}))
+
+	type NotBytes struct {
+		Trailer string `binary:"rest"`
+	}
+	_, err = Marshal(NotBytes{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "requires a []byte field")
+}
+
+func TestRestFieldOnlyAtTopLevel(t *testing.T) {
+	// A nested rest field would swallow the fields that follow its struct
+	type Nested struct {
+		Inner framedPacket
+		Tail  uint8
+	}
+	_, err := Marshal(Nested{})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	assert.Contains(t, err.Error(), "only allowed on the last field")
+	assert.ErrorIs(t, Unmarshal([]byte{1, 0, 0, 2}, &Nested{}), ErrInvalidTag)
+
+	// The same holds for a slice element, even as the last field
+	type Items struct {
+		Items []struct {
+			ID   uint8
+			Body []byte `binary:"rest"`
+		}
+		Tail uint8
+	}
+	items := Items{Tail: 9}
+	items.Items = make([]struct {
+		ID   uint8
+		Body []byte `binary:"rest"`
+	}, 2)
+	_, err = Marshal(items)
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	assert.ErrorIs(t, Unmarshal([]byte{1, 0, 0, 0, 1, 9}, &Items{}), ErrInvalidTag)
+
+	_, err = Marshal([]framedPacket{{Type: 1}})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+
+	// A rest field in an embedded struct that ends the top-level struct is allowed
+	type Frame framedPacket
+	type Embedded struct {
+		ID uint8
+		Frame
+	}
+	data := []byte{7, 2, 3, 0, 'a', 'b', 'c'}
+	var embedded Embedded
+	assert.NoError(t, Unmarshal(data, &embedded))
+	assert.Equal(t, []byte("abc"), embedded.Trailer)
+	encoded, err := Marshal(embedded)
+	assert.NoError(t, err)
+	assert.Equal(t, data, encoded)
+}
+
+func TestRestFieldBeforeSkippedFields(t *testing.T) {
+	// Fields that are never encoded may follow the rest field
+	type Packet struct {
+		Type    uint8
+		Trailer []byte `binary:"rest"`
+		Debug   string `binary:"-"`
+		cached  int
+	}
+
+	original := Packet{Type: 1, Trailer: []byte("abc"), Debug: "skipped"}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 'a', 'b', 'c'}, data)
+
+	var decoded Packet
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, Packet{Type: 1, Trailer: []byte("abc")}, decoded)
+}
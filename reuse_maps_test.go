@@ -0,0 +1,94 @@
+package binary
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type inventory struct {
+	Counts map[string]uint32
+	Tags   []string
+}
+
+func TestDecoderReuseMaps(t *testing.T) {
+	first, err := Marshal(inventory{Counts: map[string]uint32{"a": 1, "b": 2}, Tags: []string{"x"}})
+	assert.NoError(t, err)
+	second, err := Marshal(inventory{Counts: map[string]uint32{"c": 3}, Tags: []string{"y"}})
+	assert.NoError(t, err)
+
+	dec := NewDecoder(nil)
+	dec.ReuseMaps(true)
+
+	var v inventory
+	dec.Reset(first)
+	assert.NoError(t, dec.Decode(&v))
+	counts := v.Counts
+
+	// The same map is cleared and refilled, so entries from the first record are gone
+	dec.Reset(second)
+	assert.NoError(t, dec.Decode(&v))
+	assert.Equal(t, map[string]uint32{"c": 3}, v.Counts)
+	assert.Equal(t, reflect.ValueOf(counts).Pointer(), reflect.ValueOf(v.Counts).Pointer())
+	assert.Equal(t, []string{"y"}, v.Tags)
+
+	// Without the option, decoding replaces the map
+	dec.ReuseMaps(false)
+	dec.Reset(first)
+	assert.NoError(t, dec.Decode(&v))
+	assert.NotEqual(t, reflect.ValueOf(counts).Pointer(), reflect.ValueOf(v.Counts).Pointer())
+	assert.Equal(t, map[string]uint32{"c": 3}, counts)
+}
+
+func TestDecoderReuseMapsAllocations(t *testing.T) {
+	data, err := Marshal(inventory{Counts: map[string]uint32{"a": 1, "b": 2, "c": 3, "d": 4}})
+	assert.NoError(t, err)
+
+	allocs := func(reuse bool) float64 {
+		dec := NewDecoder(nil)
+		dec.ReuseMaps(reuse)
+		var v inventory
+		return testing.AllocsPerRun(10000, func() {
+			dec.Reset(data)
+			if err := dec.Decode(&v); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+	assert.Less(t, allocs(true), allocs(false))
+}
+
+func benchmarkDecodeMaps(b *testing.B, reuse bool) {
+	counts := make(map[string]uint32)
+	for _, k := range []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta", "theta"} {
+		counts[k] = uint32(len(k))
+	}
+	data, err := Marshal(inventory{Counts: counts, Tags: []string{"a", "b"}})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	dec := NewDecoder(nil)
+	dec.ReuseMaps(reuse)
+	var v inventory
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Each iteration decodes 10k records into the same struct
+		for j := 0; j < 10000; j++ {
+			dec.Reset(data)
+			if err := dec.Decode(&v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkDecodeMapsFresh(b *testing.B) {
+	benchmarkDecodeMaps(b, false)
+}
+
+func BenchmarkDecodeMapsReused(b *testing.B) {
+	benchmarkDecodeMaps(b, true)
+}
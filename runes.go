@@ -0,0 +1,44 @@
+package binary
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// runesTag makes a []rune field be written as the UTF-8 string it spells,
+// framed like a string field, instead of one 4-byte int32 per rune. An explicit
+// tag is required because rune is an alias of int32, so []rune and []int32 are
+// the same type.
+const runesTag = "runes"
+
+// encodeRunes writes a []rune as a UTF-8 string. Values that are not valid
+// Unicode code points, such as surrogate halves, are written as U+FFFD.
+func encodeRunes(slice reflect.Value, buf *encodeState, tag string) error {
+	if slice.Type().Elem().Kind() != reflect.Int32 {
+		return fmt.Errorf("%w: runes tag requires a []rune field, got %s", ErrInvalidTag, slice.Type())
+	}
+	runes := make([]rune, slice.Len())
+	for i := range runes {
+		runes[i] = rune(slice.Index(i).Int())
+	}
+	return encodeString(string(runes), buf, tag)
+}
+
+// decodeRunes reads a string written by encodeRunes and stores its runes.
+// Invalid UTF-8 in the input is decoded as U+FFFD.
+func decodeRunes(buf *decodeState, field reflect.Value, tag string) error {
+	if field.Type().Elem().Kind() != reflect.Int32 {
+		return fmt.Errorf("%w: runes tag requires a []rune field, got %s", ErrInvalidTag, field.Type())
+	}
+	var s string
+	if err := decodeString(buf, reflect.ValueOf(&s).Elem(), tag); err != nil {
+		return err
+	}
+	runes := []rune(s)
+	slice := reflect.MakeSlice(field.Type(), len(runes), len(runes))
+	for i, r := range runes {
+		slice.Index(i).SetInt(int64(r))
+	}
+	field.Set(slice)
+	return nil
+}
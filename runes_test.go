@@ -0,0 +1,77 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunesTag(t *testing.T) {
+	type Text struct {
+		Title  []rune `binary:"runes"`
+		Short  []rune `binary:"runes,prefix:1"`
+		Values []int32
+	}
+
+	original := Text{
+		Title:  []rune("héllo 世界"),
+		Short:  []rune("ok"),
+		Values: []int32{1},
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	title := "héllo 世界"
+	want := append([]byte{byte(len(title)), 0, 0, 0}, title...)
+	want = append(want, 2, 'o', 'k')
+	// Untagged []int32 keeps its 4 bytes per element
+	want = append(want, 1, 0, 0, 0, 1, 0, 0, 0)
+	assert.Equal(t, want, data)
+
+	var decoded Text
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+	assert.Equal(t, "héllo 世界", string(decoded.Title))
+}
+
+func TestRunesTagElements(t *testing.T) {
+	type Lines struct {
+		Lines  [][]rune          `binary:"runes"`
+		ByName map[string][]rune `binary:"runes,countvarint"`
+	}
+
+	original := Lines{
+		Lines:  [][]rune{[]rune("α"), {}, []rune("🎉")},
+		ByName: map[string][]rune{"k": []rune("值")},
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Lines
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestRunesTagInvalid(t *testing.T) {
+	// Surrogate halves are not valid code points and become U+FFFD
+	data, err := Marshal(struct {
+		Text []rune `binary:"runes"`
+	}{Text: []rune{'a', 0xD800}})
+	assert.NoError(t, err)
+
+	var decoded struct {
+		Text []rune `binary:"runes"`
+	}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, []rune{'a', '�'}, decoded.Text)
+
+	_, err = Marshal(struct {
+		Text []uint16 `binary:"runes"`
+	}{})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+
+	_, err = Marshal(struct {
+		Text []rune `binary:"runes,16"`
+	}{})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
@@ -0,0 +1,17 @@
+package binary
+
+import "fmt"
+
+// SafeUnmarshal is like Unmarshal but recovers from any panic raised while
+// decoding and returns it as an error. Malformed input is reported as an error
+// by Unmarshal as well; SafeUnmarshal additionally guards against panics in
+// BinaryUnmarshaler and PostUnmarshaler implementations, which makes it suitable
+// for fuzzing and for decoding untrusted input.
+func SafeUnmarshal(data []byte, v interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while unmarshaling: %v", r)
+		}
+	}()
+	return Unmarshal(data, v)
+}
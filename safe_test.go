@@ -0,0 +1,68 @@
+package binary
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type panickingUnmarshaler struct{}
+
+func (panickingUnmarshaler) MarshalBinary() ([]byte, error) { return []byte{1}, nil }
+
+func (*panickingUnmarshaler) UnmarshalBinary([]byte) error { panic("corrupt state") }
+
+// unhashableKey is registered so that it can appear as an interface map key
+type unhashableKey []uint8
+
+func init() {
+	RegisterType(105, unhashableKey{})
+}
+
+func TestSafeUnmarshalRecoversPanics(t *testing.T) {
+	type Holder struct {
+		Value panickingUnmarshaler
+	}
+	data, err := Marshal(Holder{})
+	assert.NoError(t, err)
+
+	var decoded Holder
+	assert.Panics(t, func() { _ = Unmarshal(data, &decoded) })
+
+	err = SafeUnmarshal(data, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "corrupt state")
+}
+
+func TestSafeUnmarshalMatchesUnmarshal(t *testing.T) {
+	type Message struct {
+		ID   uint32
+		Name string
+	}
+	data, err := Marshal(Message{ID: 1, Name: "a"})
+	assert.NoError(t, err)
+
+	var decoded Message
+	assert.NoError(t, SafeUnmarshal(data, &decoded))
+	assert.Equal(t, Message{ID: 1, Name: "a"}, decoded)
+
+	assert.ErrorIs(t, SafeUnmarshal(append(data, 0), &decoded), ErrTrailingData)
+	assert.Error(t, SafeUnmarshal(data[:3], &decoded))
+}
+
+func TestUnmarshalUnhashableInterfaceKey(t *testing.T) {
+	type Index struct {
+		Entries map[interface{}]uint8
+	}
+	// Craft a map whose only key is a registered slice type
+	data := []byte{1, 0, 0, 0, 1, 105, 0, 0, 0, 1, 0, 0, 0, 7, 9}
+
+	var decoded Index
+	err := Unmarshal(data, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not comparable")
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "Entries[0]", fieldErr.Path)
+}
@@ -0,0 +1,66 @@
+package binary
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaledFloat(t *testing.T) {
+	type Payment struct {
+		Amount float64 `binary:"scale:2"`
+		Rate   float32 `binary:"scale:4"`
+	}
+
+	original := Payment{Amount: 19.99, Rate: 0.0725}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Len(t, data, 16)
+	assert.Equal(t, int64(1999), int64(binary.LittleEndian.Uint64(data)))
+	assert.Equal(t, int64(725), int64(binary.LittleEndian.Uint64(data[8:])))
+
+	var decoded Payment
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, 19.99, decoded.Amount)
+	assert.Equal(t, float32(0.0725), decoded.Rate)
+}
+
+func TestScaledFloatRounding(t *testing.T) {
+	type Price struct {
+		Value float64 `binary:"scale:2"`
+	}
+
+	for _, tc := range []struct {
+		value  float64
+		stored int64
+	}{
+		{0.1 + 0.2, 30},
+		{-19.99, -1999},
+		{2.675, 267}, // 2.675 is slightly below 2.675 as a float64
+		{1e6 + 0.125, 100000012},
+		{0, 0},
+	} {
+		data, err := Marshal(Price{Value: tc.value})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.stored, int64(binary.LittleEndian.Uint64(data)), "value %v", tc.value)
+	}
+
+	var decoded Price
+	assert.NoError(t, Unmarshal([]byte{0xcf, 0x07, 0, 0, 0, 0, 0, 0}, &decoded))
+	assert.Equal(t, 19.99, decoded.Value)
+}
+
+func TestScaledFloatOutOfRange(t *testing.T) {
+	type Price struct {
+		Value float64 `binary:"scale:2"`
+	}
+
+	_, err := Marshal(Price{Value: 1e17})
+	assert.Error(t, err)
+	_, err = Marshal(Price{Value: math.NaN()})
+	assert.Error(t, err)
+	_, err = Marshal(Price{Value: math.Inf(-1)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package binary
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// schemaHashSize is the length of the layout hash written by MarshalTyped
+const schemaHashSize = 8
+
+// ErrSchemaMismatch is returned by UnmarshalTyped when the data was written for a
+// type whose layout differs from the destination's
+var ErrSchemaMismatch = errors.New("schema mismatch")
+
+// schemaHashes caches the layout hash of each type, keyed by reflect.Type
+var schemaHashes sync.Map
+
+// MarshalTyped marshals v and prepends an 8-byte little-endian hash of the
+// layout of its type, so that UnmarshalTyped can detect data written for a
+// differently shaped type. The hash covers the kinds, nesting and tags of the
+// encoded fields, but not their names.
+func MarshalTyped(v interface{}) ([]byte, error) {
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		return nil, fmt.Errorf("cannot marshal nil value")
+	}
+	data := binary.LittleEndian.AppendUint64(nil, schemaHash(typ))
+	data, err := MarshalAppend(data, v)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// UnmarshalTyped verifies the layout hash written by MarshalTyped against the
+// type v points to and then unmarshals the rest of the data into v. If the hash
+// differs, v is left untouched and an error wrapping ErrSchemaMismatch is returned.
+func UnmarshalTyped(data []byte, v interface{}) error {
+	typ := reflect.TypeOf(v)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return fmt.Errorf("only pointers are supported for unmarshaling")
+	}
+	if len(data) < schemaHashSize {
+		return fmt.Errorf("typed data of %d bytes has no schema header: %w", len(data), errUnexpectedEOF)
+	}
+	want := binary.LittleEndian.Uint64(data)
+	if got := schemaHash(typ); got != want {
+		return fmt.Errorf("%w: %s has layout hash %016x, data has %016x", ErrSchemaMismatch, typ.Elem(), got, want)
+	}
+	return Unmarshal(data[schemaHashSize:], v)
+}
+
+// schemaHash returns the cached layout hash of typ. Top-level pointers are
+// dereferenced, like Marshal does, so T and *T share a hash.
+func schemaHash(typ reflect.Type) uint64 {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if hash, ok := schemaHashes.Load(typ); ok {
+		return hash.(uint64)
+	}
+	var sb strings.Builder
+	describeLayout(&sb, typ, make(map[reflect.Type]int))
+	h := fnv.New64a()
+	h.Write([]byte(sb.String()))
+	hash := h.Sum64()
+	schemaHashes.Store(typ, hash)
+	return hash
+}
+
+// describeLayout writes a canonical description of how values of typ are encoded.
+// Recursive types refer back to the enclosing type by its nesting level.
+func describeLayout(sb *strings.Builder, typ reflect.Type, visiting map[reflect.Type]int) {
+	if level, ok := visiting[typ]; ok {
+		sb.WriteString("^" + strconv.Itoa(level))
+		return
+	}
+	if isBuiltinType(typ) || usesCustomCodec(typ) {
+		// The encoding of these types is defined by the type itself
+		sb.WriteString(typ.String())
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		sb.WriteString("*")
+		describeLayout(sb, typ.Elem(), visiting)
+	case reflect.Slice:
+		sb.WriteString("[]")
+		describeLayout(sb, typ.Elem(), visiting)
+	case reflect.Array:
+		sb.WriteString("[" + strconv.Itoa(typ.Len()) + "]")
+		describeLayout(sb, typ.Elem(), visiting)
+	case reflect.Map:
+		sb.WriteString("map[")
+		describeLayout(sb, typ.Key(), visiting)
+		sb.WriteString("]")
+		describeLayout(sb, typ.Elem(), visiting)
+	case reflect.Struct:
+		visiting[typ] = len(visiting)
+		defer delete(visiting, typ)
+		sb.WriteString("{")
+		for _, i := range fieldOrder(typ) {
+			field := typ.Field(i)
+			tag := field.Tag.Get("binary")
+			if !field.IsExported() || tag == "-" {
+				continue
+			}
+			describeLayout(sb, field.Type, visiting)
+			if tag != "" {
+				sb.WriteString(" " + strconv.Quote(tag))
+			}
+			sb.WriteString(";")
+		}
+		sb.WriteString("}")
+	default:
+		// Scalars and interfaces, whose values carry their own type code
+		sb.WriteString(typ.Kind().String())
+	}
+}
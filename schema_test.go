@@ -0,0 +1,94 @@
+package binary
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type orderV1 struct {
+	ID    uint32
+	Items []string
+	Note  string `binary:"-"`
+}
+
+type orderRenamed struct {
+	Number uint32
+	Lines  []string
+}
+
+type orderV2 struct {
+	ID    uint32
+	Items []string `binary:"countvarint"`
+}
+
+type treeNode struct {
+	Value    uint8
+	Children []*treeNode
+}
+
+func TestTypedRoundTrip(t *testing.T) {
+	original := orderV1{ID: 5, Items: []string{"a", "b"}}
+	data, err := MarshalTyped(original)
+	assert.NoError(t, err)
+
+	plain, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, plain, data[schemaHashSize:])
+
+	var decoded orderV1
+	assert.NoError(t, UnmarshalTyped(data, &decoded))
+	assert.Equal(t, original, decoded)
+
+	// Field names and ignored fields are not part of the layout
+	var renamed orderRenamed
+	assert.NoError(t, UnmarshalTyped(data, &renamed))
+	assert.Equal(t, orderRenamed{Number: 5, Lines: []string{"a", "b"}}, renamed)
+
+	// Pointers are dereferenced like in Marshal
+	fromPointer, err := MarshalTyped(&original)
+	assert.NoError(t, err)
+	assert.Equal(t, data, fromPointer)
+}
+
+func TestTypedSchemaMismatch(t *testing.T) {
+	data, err := MarshalTyped(orderV1{ID: 5, Items: []string{"a"}})
+	assert.NoError(t, err)
+
+	// A different tag changes the layout, even though the fields look alike
+	decoded := orderV2{ID: 9}
+	err = UnmarshalTyped(data, &decoded)
+	assert.ErrorIs(t, err, ErrSchemaMismatch)
+	assert.Equal(t, orderV2{ID: 9}, decoded)
+
+	var other struct {
+		ID    uint64
+		Items []string
+	}
+	assert.ErrorIs(t, UnmarshalTyped(data, &other), ErrSchemaMismatch)
+
+	var scalar uint32
+	assert.ErrorIs(t, UnmarshalTyped(data, &scalar), ErrSchemaMismatch)
+}
+
+func TestTypedRecursiveType(t *testing.T) {
+	leaf := func(v uint8) *treeNode { return &treeNode{Value: v, Children: []*treeNode{}} }
+	original := treeNode{Value: 1, Children: []*treeNode{leaf(2), {Value: 3, Children: []*treeNode{leaf(4)}}}}
+	data, err := MarshalTyped(original)
+	assert.NoError(t, err)
+
+	var decoded treeNode
+	assert.NoError(t, UnmarshalTyped(data, &decoded))
+	assert.Equal(t, original, decoded)
+	assert.Equal(t, schemaHash(reflect.TypeOf(original)), schemaHash(reflect.TypeOf(&original)))
+}
+
+func TestTypedErrors(t *testing.T) {
+	_, err := MarshalTyped(nil)
+	assert.Error(t, err)
+
+	var v orderV1
+	assert.Error(t, UnmarshalTyped([]byte{1, 2, 3}, &v))
+	assert.Error(t, UnmarshalTyped(make([]byte, 8), v))
+}
@@ -0,0 +1,60 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeFromChunkedReader(t *testing.T) {
+	type Record struct {
+		Name   string
+		Data   []byte
+		Fixed  string `binary:"16"`
+		Raw    []byte `binary:"8"`
+		ID     [12]byte
+		Custom CustomType
+	}
+
+	original := Record{
+		Name:   strings.Repeat("name", 100),
+		Data:   bytes.Repeat([]byte{1, 2, 3}, 100),
+		Fixed:  "fixed-width",
+		Raw:    []byte{9, 8, 7, 6, 5, 4, 3, 2},
+		ID:     [12]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+		Custom: CustomType{Value: "custom"},
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	// A reader returning one byte per Read call must not truncate any field
+	var decoded Record
+	err = NewDecoder(iotest.OneByteReader(bytes.NewReader(data))).Decode(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
+
+func TestDecodeShortReadReturnsUnexpectedEOF(t *testing.T) {
+	type Record struct {
+		Name  string
+		Fixed string `binary:"16"`
+	}
+
+	data, err := Marshal(Record{Name: "hello", Fixed: "world"})
+	assert.NoError(t, err)
+
+	// Cut in the middle of the length-prefixed field
+	var decoded Record
+	err = NewDecoder(iotest.HalfReader(bytes.NewReader(data[:6]))).Decode(&decoded)
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "got %v", err)
+
+	// Cut in the middle of the fixed-length field
+	err = NewDecoder(iotest.OneByteReader(bytes.NewReader(data[:len(data)-4]))).Decode(&decoded)
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "got %v", err)
+}
@@ -0,0 +1,76 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Person struct {
+	Name      string
+	Age       uint8
+	Email     string    `binary:"50"`
+	Data      []byte    `binary:"10"`
+	Scores    []uint32  `binary:"5"`
+	ID        [16]byte  `binary:"16"`
+	Values    [4]uint32 `binary:"4"`
+	Address   string
+	Height    float32
+	Weight    float64
+	HaveChild bool
+}
+
+func examplePerson() Person {
+	return Person{
+		Name:      "Alice",
+		Age:       30,
+		Email:     "alice@example.com",
+		Data:      []byte{1, 2, 3, 4, 5},
+		Scores:    []uint32{100, 95, 87},
+		ID:        [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		Values:    [4]uint32{1000, 2000, 3000, 4000},
+		Address:   "Main St 123",
+		Height:    165.5,
+		Weight:    62.3,
+		HaveChild: true,
+	}
+}
+
+func TestSizeMatchesMarshal(t *testing.T) {
+	values := []interface{}{
+		examplePerson(),
+		&Person{},
+		[]uint32{1, 2, 3, 4, 5},
+		[]string{"a", "bb", ""},
+		[][]uint16{{1}, {2, 3}, {}},
+		[3]int64{1, 2, 3},
+		"hello",
+		uint8(1),
+		CustomType{Value: "custom"},
+		struct {
+			Custom CustomType
+			Amount string `binary:"scale:2"`
+		}{CustomType{Value: "x"}, "1.25"},
+	}
+
+	for _, v := range values {
+		data, err := Marshal(v)
+		assert.NoError(t, err)
+
+		size, err := Size(v)
+		assert.NoError(t, err)
+		assert.Equal(t, len(data), size, "value %#v", v)
+	}
+}
+
+func TestSizeExamplePerson(t *testing.T) {
+	size, err := Size(examplePerson())
+	assert.NoError(t, err)
+	// Name(4+5) Age(1) Email(50) Data(10) Scores(5*4) ID(16) Values(4*4) Address(4+11) Height(4) Weight(8) HaveChild(1)
+	assert.Equal(t, 150, size)
+}
+
+func TestSizeUnsupportedType(t *testing.T) {
+	_, err := Size(make(chan int))
+	assert.Error(t, err)
+}
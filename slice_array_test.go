@@ -168,7 +168,7 @@ func TestEncodeUnsupportedChannelType(t *testing.T) {
 	ch := make(chan int)
 	_, err := Marshal(ch)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported type")
+	assert.ErrorIs(t, err, ErrUnsupportedType)
 }
 
 func TestEncodeUnsupportedFuncType(t *testing.T) {
@@ -176,15 +176,15 @@ func TestEncodeUnsupportedFuncType(t *testing.T) {
 	fn := func() {}
 	_, err := Marshal(fn)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported type")
+	assert.ErrorIs(t, err, ErrUnsupportedType)
 }
 
 func TestEncodeUnsupportedMapType(t *testing.T) {
-	// Test encoding a map (not supported)
-	m := make(map[string]int)
+	// Test encoding a map with an unsupported value type
+	m := map[string]chan int{"a": make(chan int)}
 	_, err := Marshal(m)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported type")
+	assert.ErrorIs(t, err, ErrUnsupportedType)
 }
 
 func TestEncodeUnsupportedPointerType(t *testing.T) {
@@ -193,19 +193,19 @@ func TestEncodeUnsupportedPointerType(t *testing.T) {
 	ch := make(chan int)
 	_, err := Marshal(&ch)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported type")
+	assert.ErrorIs(t, err, ErrUnsupportedType)
 
 	// Pointer to function should fail
 	fn := func() {}
 	_, err = Marshal(&fn)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported type")
+	assert.ErrorIs(t, err, ErrUnsupportedType)
 
-	// Pointer to map should fail
-	m := make(map[string]int)
+	// Pointer to map with an unsupported value type should fail
+	m := map[string]func(){"a": func() {}}
 	_, err = Marshal(&m)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported type")
+	assert.ErrorIs(t, err, ErrUnsupportedType)
 }
 
 func TestDecodeToUnsupportedChannelType(t *testing.T) {
@@ -214,7 +214,7 @@ func TestDecodeToUnsupportedChannelType(t *testing.T) {
 	var ch chan int
 	err := Unmarshal(data, &ch)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported type")
+	assert.ErrorIs(t, err, ErrUnsupportedType)
 }
 
 func TestDecodeToUnsupportedFuncType(t *testing.T) {
@@ -223,16 +223,16 @@ func TestDecodeToUnsupportedFuncType(t *testing.T) {
 	var fn func()
 	err := Unmarshal(data, &fn)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported type")
+	assert.ErrorIs(t, err, ErrUnsupportedType)
 }
 
 func TestDecodeToUnsupportedMapType(t *testing.T) {
-	// Test decoding to a map (not supported)
-	data := []byte{1, 2, 3, 4}
-	var m map[string]int
+	// Test decoding to a map with an unsupported value type
+	data := []byte{1, 0, 0, 0, 0, 0, 0, 0}
+	var m map[string]chan int
 	err := Unmarshal(data, &m)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported type")
+	assert.ErrorIs(t, err, ErrUnsupportedType)
 }
 
 func TestDecodeWithMalformedData(t *testing.T) {
@@ -0,0 +1,50 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// MarshalSliceFunc serializes a slice using enc to encode each element.
+// The output uses the same framing as Marshal for slices: a uint32 element
+// count followed by the elements, so only the element encoding is customized.
+func MarshalSliceFunc[T any](s []T, enc func(T, io.Writer) error) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, uint32(len(s))); err != nil {
+		return nil, err
+	}
+	for i, elem := range s {
+		if err := enc(elem, &buf); err != nil {
+			return nil, fmt.Errorf("error encoding element %d: %w", i, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalSliceFunc deserializes data produced by MarshalSliceFunc, using dec
+// to decode each element. Like Unmarshal, it returns an error if any bytes
+// remain after the last element.
+func UnmarshalSliceFunc[T any](data []byte, dec func(io.Reader) (T, error)) ([]T, error) {
+	r := bytes.NewReader(data)
+	var length uint32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return nil, err
+	}
+
+	// Every element needs at least one byte, so never preallocate more than the input can hold
+	s := make([]T, 0, min(int(length), r.Len()))
+	for i := 0; i < int(length); i++ {
+		elem, err := dec(r)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding element %d: %w", i, err)
+		}
+		s = append(s, elem)
+	}
+
+	if r.Len() > 0 {
+		return nil, &TrailingDataError{Remaining: r.Len()}
+	}
+	return s, nil
+}
@@ -0,0 +1,73 @@
+package binary
+
+import (
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSliceFuncRoundTrip(t *testing.T) {
+	// Encode each element as a single byte offset from an external base value
+	base := 1000
+	enc := func(v int, w io.Writer) error {
+		_, err := w.Write([]byte{byte(v - base)})
+		return err
+	}
+	dec := func(r io.Reader) (int, error) {
+		var b [1]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		return base + int(b[0]), nil
+	}
+
+	original := []int{1000, 1001, 1255}
+	data, err := MarshalSliceFunc(original, enc)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{3, 0, 0, 0, 0, 1, 255}, data)
+
+	decoded, err := UnmarshalSliceFunc(data, dec)
+	assert.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
+
+func TestSliceFuncMatchesMarshal(t *testing.T) {
+	enc := func(v uint16, w io.Writer) error {
+		return binary.Write(w, binary.LittleEndian, v)
+	}
+	original := []uint16{1, 2, 3}
+
+	data, err := MarshalSliceFunc(original, enc)
+	assert.NoError(t, err)
+
+	// With a plain element codec the output is identical to Marshal
+	expected, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, data)
+
+	var decoded []uint16
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestSliceFuncErrors(t *testing.T) {
+	dec := func(r io.Reader) (uint8, error) {
+		var v uint8
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	}
+
+	// Count claims more elements than present
+	_, err := UnmarshalSliceFunc([]byte{0xFF, 0xFF, 0xFF, 0xFF, 1}, dec)
+	assert.Error(t, err)
+
+	// Trailing data
+	_, err = UnmarshalSliceFunc([]byte{1, 0, 0, 0, 1, 2}, dec)
+	assert.Error(t, err)
+
+	// Encoder errors propagate
+	_, err = MarshalSliceFunc([]int{1}, func(int, io.Writer) error { return io.ErrShortWrite })
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+}
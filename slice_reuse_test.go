@@ -0,0 +1,80 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeSliceReusesCapacity(t *testing.T) {
+	data, err := Marshal([]uint32{1, 2, 3})
+	assert.NoError(t, err)
+
+	decoded := make([]uint32, 0, 8)
+	backing := &decoded[:1][0]
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, []uint32{1, 2, 3}, decoded)
+	assert.Equal(t, 8, cap(decoded))
+	assert.Same(t, backing, &decoded[0])
+
+	// A destination that is too small is replaced
+	small := make([]uint32, 0, 2)
+	assert.NoError(t, Unmarshal(data, &small))
+	assert.Equal(t, []uint32{1, 2, 3}, small)
+}
+
+func TestDecodeSliceReuseClearsStaleElements(t *testing.T) {
+	type Item struct {
+		ID   uint16
+		Note string `binary:"-"`
+	}
+
+	data, err := Marshal([]Item{{ID: 1}, {ID: 2}})
+	assert.NoError(t, err)
+
+	decoded := []Item{{ID: 9, Note: "stale"}, {ID: 8, Note: "stale"}, {ID: 7}}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, []Item{{ID: 1}, {ID: 2}}, decoded)
+
+	// Fixed-length slices reuse the destination as well
+	type Fixed struct {
+		Values []uint16 `binary:"2"`
+	}
+	data, err = Marshal(Fixed{Values: []uint16{4, 5}})
+	assert.NoError(t, err)
+	fixed := Fixed{Values: make([]uint16, 3, 4)}
+	backing := &fixed.Values[0]
+	assert.NoError(t, Unmarshal(data, &fixed))
+	assert.Equal(t, []uint16{4, 5}, fixed.Values)
+	assert.Same(t, backing, &fixed.Values[0])
+}
+
+func BenchmarkDecodeSliceFresh(b *testing.B) {
+	data, err := Marshal(make([]uint64, 256))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded []uint64
+		if err := Unmarshal(data, &decoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeSliceReused(b *testing.B) {
+	data, err := Marshal(make([]uint64, 256))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var decoded []uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Unmarshal(data, &decoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
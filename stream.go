@@ -0,0 +1,155 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// Encoder writes binary-encoded values to an output stream
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns a new encoder that writes to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the binary encoding of v to the stream. Values are written
+// directly to the underlying writer using the same format as Marshal, and
+// consecutive calls append one value after another. Wrap the writer in a
+// bufio.Writer when it is expensive to write small pieces of data.
+func (e *Encoder) Encode(v interface{}) error {
+	return encodeValue(v, &encodeState{w: e.w})
+}
+
+// Decoder reads and decodes binary values from an input stream
+type Decoder struct {
+	r                 io.Reader
+	br                *bytes.Reader // reused by Reset
+	state             decodeState   // reused by Decode to avoid allocating per value
+	maxAlloc          int
+	maxDepth          int
+	zeroFillOnEOF     bool
+	clearBeforeDecode bool
+	reuseMaps         bool
+	truncated         bool
+	options           codecOptions // set by Codec.UnmarshalReader
+}
+
+// NewDecoder returns a new decoder that reads from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// UnmarshalReader decodes a single value from r without reading the whole input
+// into memory first. Like Decoder.Decode, it reads exactly the bytes of the value
+// and leaves r positioned at the byte after it, and returns io.EOF if r is
+// already at the end of its input. Use a Decoder to read many values or to set
+// decoding options.
+func UnmarshalReader(r io.Reader, v interface{}) error {
+	return defaultCodec.UnmarshalReader(r, v)
+}
+
+// Reset discards any remaining input and makes the decoder read from data.
+// The reader used for data is reused across calls and options are kept, so a
+// single decoder can decode many in-memory records without allocating a new
+// reader or decoder for each of them.
+func (d *Decoder) Reset(data []byte) {
+	if d.br == nil {
+		d.br = bytes.NewReader(data)
+	} else {
+		d.br.Reset(data)
+	}
+	d.r = d.br
+	d.truncated = false
+}
+
+// SetMaxAllocSize limits the length that a single length prefix may declare,
+// counted in bytes for strings and []byte and in elements for slices.
+// Decoding fails before allocating when a prefix exceeds the limit, which
+// protects against corrupt or hostile input on streams whose size is unknown.
+// A value of 0, the default, disables the limit.
+func (d *Decoder) SetMaxAllocSize(n int) {
+	d.maxAlloc = n
+}
+
+// SetMaxDepth limits how deeply structs, slices, arrays, maps and interfaces
+// may be nested in a decoded value. Decoding fails with an error once the limit
+// is exceeded, instead of exhausting the stack on input that implies excessive
+// nesting. A value of 0 restores the default of 1000 levels, which also applies
+// to Unmarshal.
+func (d *Decoder) SetMaxDepth(n int) {
+	d.maxDepth = n
+}
+
+// ZeroFillOnEOF controls how fixed-length fields (e.g. `binary:"32"`) that are
+// cut short by the end of input are handled. When enabled, the bytes that are
+// available are kept, the rest of the field is zero-filled and Decode succeeds;
+// Truncated then reports that the value was salvaged from incomplete data.
+// It is disabled by default, in which case such fields cause an error.
+func (d *Decoder) ZeroFillOnEOF(enable bool) {
+	d.zeroFillOnEOF = enable
+}
+
+// ClearBeforeDecode controls whether Decode zeroes the destination value before
+// populating it. Enable it when decoding repeatedly into the same value so that
+// fields not written by the current record (such as fields tagged "-") do not
+// keep stale data from a previous record. It is disabled by default.
+func (d *Decoder) ClearBeforeDecode(enable bool) {
+	d.clearBeforeDecode = enable
+}
+
+// ReuseMaps controls whether Decode refills the non-nil maps of the destination
+// value instead of allocating new ones. When enabled, an existing map is cleared
+// and keeps its allocated buckets, which saves allocations when decoding many
+// records into the same value; slices already reuse their backing arrays when
+// they have enough capacity. Maps shared with other code are modified in place,
+// and the option has no effect together with ClearBeforeDecode, which replaces
+// the maps with nil. It is disabled by default.
+func (d *Decoder) ReuseMaps(enable bool) {
+	d.reuseMaps = enable
+}
+
+// Truncated reports whether the last call to Decode zero-filled a fixed-length field
+func (d *Decoder) Truncated() bool {
+	return d.truncated
+}
+
+// Decode reads the next binary-encoded value from the input and stores it in the value pointed to by v.
+// It reads exactly the bytes of one value, leaving the input positioned at the next one.
+// At the end of the input, Decode returns io.EOF if no byte of the value could be read,
+// and an error matching ErrTruncated and io.ErrUnexpectedEOF if the input ends in the middle of the value.
+// A value implementing BinaryUnmarshaler has no framing of its own and consumes the rest of the input.
+func (d *Decoder) Decode(v interface{}) error {
+	d.truncated = false
+
+	if unmarshaler, ok := asUnmarshaler(v); ok {
+		data, err := io.ReadAll(d.r)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return io.EOF
+		}
+		return unmarshaler.UnmarshalBinary(data)
+	}
+
+	d.state = decodeState{
+		Reader:            d.r,
+		codecOptions:      d.options,
+		maxAlloc:          d.maxAlloc,
+		maxDepth:          d.maxDepth,
+		zeroFillOnEOF:     d.zeroFillOnEOF,
+		clearBeforeDecode: d.clearBeforeDecode,
+		reuseMaps:         d.reuseMaps,
+	}
+	err := decodeValue(v, &d.state)
+	d.truncated = d.state.truncated
+	if err != nil && d.state.n == 0 && (errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)) {
+		// The input ended cleanly between two values
+		return io.EOF
+	}
+	return err
+}
@@ -201,3 +201,27 @@ func TestDirectArrayIgnore(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, reflect.DeepEqual(original, decoded))
 }
+
+// TestCustomMarshalerIgnoreTag tests that "-" also skips fields with their own marshaler
+func TestCustomMarshalerIgnoreTag(t *testing.T) {
+	type TestStruct struct {
+		Pointer *CustomType    `binary:"-"`
+		Value   CustomType     `binary:"-"`
+		Version pointerVersion `binary:"-"`
+		Number  uint8
+	}
+
+	original := TestStruct{
+		Pointer: &CustomType{Value: "p"},
+		Value:   CustomType{Value: "v"},
+		Version: pointerVersion{Major: 1, Minor: 2},
+		Number:  7,
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{7}, data)
+
+	var decoded TestStruct
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, TestStruct{Number: 7}, decoded)
+}
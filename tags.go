@@ -1,30 +1,132 @@
 package binary
 
 import (
+	"encoding/binary"
 	"fmt"
 	"strconv"
 	"strings"
 )
 
-// parseTag parses the tag to extract length specification
-func parseTag(tag string) (uint32, error) {
+// tagInfo holds the options of a `binary` struct tag, parsed once from its
+// comma-separated form
+type tagInfo struct {
+	Ignore          bool   // "-": the field is skipped
+	FixedLen        uint32 // length from "N", "len:N", "count:N", "cstr:N" or "raw:N"
+	HasFixedLen     bool   // the field has a fixed length and no length prefix
+	CString         bool   // "cstr:N": NUL-terminated string in a fixed field
+	Raw             bool   // "raw:N": trailing NUL bytes are kept when decoding
+	PrefixBytes     int    // width of the length prefix: 1, 2, 4 or 8 bytes
+	HasPrefixBytes  bool   // the width was set with "prefix:N"
+	PrefixBigEndian bool   // "prefixbe": the length prefix is big-endian
+	CountVarint     bool   // "countvarint": the length prefix is an unsigned varint
+	Varint          bool   // "varint": integers are written as varints
+	Decimal         bool   // "decimal": floats are written as decimal strings
+	Float32         bool   // "f32": float64 values are written as 4-byte float32
+	OmitEmpty       bool   // "omitempty": a presence byte precedes the field
+	Packed          bool   // "packed": a []bool is written 8 elements per byte
+	UTF16           bool   // "utf16": strings are written as UTF-16 code units
+	Runes           bool   // "runes": a []rune is written as a UTF-8 string
+	ElemLen         uint32 // fixed length of each element from "elem:N"
+	HasElemLen      bool   // the elements of the field have a fixed length
+	Scale           int    // decimal places from "scale:N"
+	HasScale        bool   // the field is a fixed-point decimal
+}
+
+// parseTag parses a comma-separated tag into a tagInfo. An empty tag yields the
+// default options, and unknown or malformed options are reported as errors.
+func parseTag(tag string) (tagInfo, error) {
+	info := tagInfo{PrefixBytes: 4}
 	if tag == "" {
-		return 0, fmt.Errorf("empty tag")
+		return info, nil
 	}
 
-	// If tag is "-", it means to ignore the tag
+	// If tag is "-", it means to ignore the field
 	if tag == "-" {
-		return 0, fmt.Errorf("ignore tag")
+		info.Ignore = true
+		return info, nil
+	}
+
+	for option := range strings.SplitSeq(tag, ",") {
+		switch {
+		case option == "prefixbe":
+			info.PrefixBigEndian = true
+		case option == "countvarint":
+			info.CountVarint = true
+		case option == "varint":
+			info.Varint = true
+		case option == "decimal":
+			info.Decimal = true
+		case option == "f32":
+			info.Float32 = true
+		case option == "omitempty":
+			info.OmitEmpty = true
+		case option == packedTag:
+			info.Packed = true
+		case option == utf16Tag:
+			info.UTF16 = true
+		case option == runesTag:
+			info.Runes = true
+		case option == "bits" || option == "rest" || strings.HasPrefix(option, "group:") || strings.HasPrefix(option, lenFromPrefix) || strings.HasPrefix(option, orderPrefix):
+			// Handled by the struct field loops before the tag is parsed
+		case strings.HasPrefix(option, "prefix:"):
+			width, err := strconv.Atoi(strings.TrimPrefix(option, "prefix:"))
+			if err != nil || (width != 1 && width != 2 && width != 4 && width != 8) {
+				return info, fmt.Errorf("%w: %s", ErrInvalidTag, option)
+			}
+			info.PrefixBytes = width
+			info.HasPrefixBytes = true
+		case strings.HasPrefix(option, elemPrefix):
+			length, err := strconv.ParseUint(strings.TrimPrefix(option, elemPrefix), 10, 32)
+			if err != nil {
+				return info, fmt.Errorf("%w: %s", ErrInvalidTag, option)
+			}
+			info.ElemLen = uint32(length)
+			info.HasElemLen = true
+		case strings.HasPrefix(option, "scale:"):
+			scale, err := strconv.Atoi(strings.TrimPrefix(option, "scale:"))
+			if err != nil || scale < 0 || scale > maxDecimalScale {
+				return info, fmt.Errorf("%w: %s", ErrInvalidTag, option)
+			}
+			info.Scale = scale
+			info.HasScale = true
+		default:
+			length, err := parseFixedLength(option)
+			if err != nil {
+				return info, err
+			}
+			info.FixedLen = length
+			info.HasFixedLen = true
+			info.CString = strings.HasPrefix(option, "cstr:")
+			info.Raw = strings.HasPrefix(option, "raw:")
+		}
+	}
+	if info.CountVarint && info.HasPrefixBytes {
+		return info, fmt.Errorf("%w: countvarint cannot be combined with prefix:N", ErrInvalidTag)
+	}
+	if info.Packed && info.HasFixedLen {
+		return info, fmt.Errorf("%w: packed cannot be combined with a fixed length", ErrInvalidTag)
+	}
+	if info.UTF16 && info.HasFixedLen {
+		return info, fmt.Errorf("%w: utf16 cannot be combined with a fixed length", ErrInvalidTag)
 	}
+	if info.Runes && info.HasFixedLen {
+		return info, fmt.Errorf("%w: runes cannot be combined with a fixed length", ErrInvalidTag)
+	}
+	return info, nil
+}
 
+// parseFixedLength parses a length option in "N", "len:N", "count:N", "cstr:N" or "raw:N" format.
+// "count:N" is an alias of "N" that reads better on slices and arrays, where the
+// length is an element count, next to "elem:N".
+func parseFixedLength(option string) (uint32, error) {
 	// Try to parse as integer
-	if length, err := strconv.ParseUint(tag, 10, 32); err == nil {
+	if length, err := strconv.ParseUint(option, 10, 32); err == nil {
 		return uint32(length), nil
 	}
 
-	// Try to parse as "len:N" format
-	if strings.HasPrefix(tag, "len:") {
-		parts := strings.Split(tag, ":")
+	// Try to parse as "len:N", "count:N", "cstr:N" or "raw:N" format
+	if strings.HasPrefix(option, "len:") || strings.HasPrefix(option, "count:") || strings.HasPrefix(option, "cstr:") || strings.HasPrefix(option, "raw:") {
+		parts := strings.Split(option, ":")
 		if len(parts) == 2 {
 			if length, err := strconv.ParseUint(parts[1], 10, 32); err == nil {
 				return uint32(length), nil
@@ -32,5 +134,76 @@ func parseTag(tag string) (uint32, error) {
 		}
 	}
 
-	return 0, fmt.Errorf("invalid tag format: %s", tag)
-}
\ No newline at end of file
+	return 0, fmt.Errorf("%w: %s", ErrInvalidTag, option)
+}
+
+// lengthPrefix describes how the length prefix of a variable-length field is written
+type lengthPrefix struct {
+	width  int              // size of the prefix in bytes: 1, 2, 4 or 8
+	order  binary.ByteOrder // byte order of the prefix
+	varint bool             // the prefix is an unsigned varint instead of a fixed-width integer
+}
+
+// prefix returns the length prefix selected by the tag. "prefix:N" selects a
+// prefix width of 1, 2, 4 or 8 bytes and "prefixbe" selects big-endian prefixes,
+// while the field's elements keep the codec's byte order, and "countvarint"
+// writes the prefix as an unsigned varint. Options can be combined with a
+// comma, e.g. "prefix:2,prefixbe". Without them the prefix has the codec's
+// width and byte order, a little-endian uint32 by default.
+func (info tagInfo) prefix(options codecOptions) lengthPrefix {
+	prefix := lengthPrefix{width: info.PrefixBytes, order: options.byteOrder(), varint: info.CountVarint}
+	if !info.HasPrefixBytes && options.prefixBytes != 0 {
+		prefix.width = options.prefixBytes
+	}
+	if info.PrefixBigEndian {
+		prefix.order = binary.BigEndian
+	}
+	return prefix
+}
+
+// hasTagOption reports whether a comma-separated tag contains the given option.
+// It is called for every encoded value, so it iterates without allocating.
+func hasTagOption(tag string, option string) bool {
+	for o := range strings.SplitSeq(tag, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutTagOption returns a comma-separated tag with the given option removed
+func withoutTagOption(tag string, option string) string {
+	var options []string
+	for _, o := range strings.Split(tag, ",") {
+		if o != option {
+			options = append(options, o)
+		}
+	}
+	return strings.Join(options, ",")
+}
+
+// elemPrefix starts the tag option that fixes the length of each element of a
+// slice, array or map, e.g. `binary:"count:4,elem:16"` for four 16-byte strings.
+// The element length is passed to the elements as a plain "N" option.
+const elemPrefix = "elem:"
+
+// elementTag returns the tag that is passed down to the elements of a slice, array or map.
+// Length-prefix options apply to nested prefixes as well, so that all framing of a
+// field is consistent, varint, decimal and f32 apply to integer and float elements and
+// utf16 and runes to string and []rune elements; fixed lengths only apply to the field itself, while
+// "elem:N" becomes the fixed length of the elements.
+func elementTag(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	var options []string
+	for option := range strings.SplitSeq(tag, ",") {
+		if length, ok := strings.CutPrefix(option, elemPrefix); ok {
+			options = append(options, length)
+		} else if option == "prefixbe" || option == "varint" || option == "decimal" || option == "f32" || option == "countvarint" || option == utf16Tag || option == runesTag || strings.HasPrefix(option, "prefix:") {
+			options = append(options, option)
+		}
+	}
+	return strings.Join(options, ",")
+}
@@ -0,0 +1,43 @@
+package binary
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// MarshalHex is like Marshal but returns the data as a lowercase hex string
+func MarshalHex(v interface{}) (string, error) {
+	data, err := Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// UnmarshalHex decodes a hex string produced by MarshalHex and unmarshals the data into v
+func UnmarshalHex(s string, v interface{}) error {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("invalid hex data: %w", err)
+	}
+	return Unmarshal(data, v)
+}
+
+// MarshalBase64 is like Marshal but returns the data as a padded standard base64 string
+func MarshalBase64(v interface{}) (string, error) {
+	data, err := Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// UnmarshalBase64 decodes a base64 string produced by MarshalBase64 and unmarshals the data into v
+func UnmarshalBase64(s string, v interface{}) error {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("invalid base64 data: %w", err)
+	}
+	return Unmarshal(data, v)
+}
@@ -0,0 +1,50 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalHex(t *testing.T) {
+	type Config struct {
+		Name string
+		Port uint16
+	}
+
+	original := Config{Name: "ab", Port: 0x1f90}
+	s, err := MarshalHex(original)
+	assert.NoError(t, err)
+	assert.Equal(t, "020000006162901f", s)
+
+	var decoded Config
+	assert.NoError(t, UnmarshalHex(s, &decoded))
+	assert.Equal(t, original, decoded)
+
+	assert.Error(t, UnmarshalHex("0g", &decoded))
+	assert.Error(t, UnmarshalHex("0200", &decoded))
+
+	_, err = MarshalHex(make(chan int))
+	assert.Error(t, err)
+}
+
+func TestMarshalBase64(t *testing.T) {
+	type Config struct {
+		Name string
+		Port uint16
+	}
+
+	original := Config{Name: "ab", Port: 0x1f90}
+	s, err := MarshalBase64(original)
+	assert.NoError(t, err)
+	assert.Equal(t, "AgAAAGFikB8=", s)
+
+	var decoded Config
+	assert.NoError(t, UnmarshalBase64(s, &decoded))
+	assert.Equal(t, original, decoded)
+
+	assert.Error(t, UnmarshalBase64("not base64!", &decoded))
+
+	_, err = MarshalBase64(make(chan int))
+	assert.Error(t, err)
+}
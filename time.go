@@ -0,0 +1,46 @@
+package binary
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// encodeTime serializes a time.Time as Unix seconds (int64), nanoseconds within
+// the second (uint32) and the zone offset east of UTC in seconds (int32).
+// Seconds rather than Unix nanoseconds are used so that every representable time,
+// including the zero time, round-trips exactly. The monotonic clock reading is dropped.
+func encodeTime(t time.Time, buf *encodeState) error {
+	_, offset := t.Zone()
+	var data [16]byte
+	buf.byteOrder().PutUint64(data[0:], uint64(t.Unix()))
+	buf.byteOrder().PutUint32(data[8:], uint32(t.Nanosecond()))
+	buf.byteOrder().PutUint32(data[12:], uint32(int32(offset)))
+	_, err := buf.Write(data[:])
+	return err
+}
+
+// decodeTime deserializes a time.Time written by encodeTime.
+// Times with a zero offset are decoded in UTC, others in a fixed zone with the encoded offset.
+func decodeTime(buf *decodeState, field reflect.Value) error {
+	var data [16]byte
+	if err := buf.readFull(data[:]); err != nil {
+		return err
+	}
+
+	sec := int64(buf.byteOrder().Uint64(data[0:]))
+	nsec := buf.byteOrder().Uint32(data[8:])
+	offset := int32(buf.byteOrder().Uint32(data[12:]))
+	if nsec >= uint32(time.Second) {
+		return fmt.Errorf("invalid time nanoseconds: %d", nsec)
+	}
+
+	t := time.Unix(sec, int64(nsec)).UTC()
+	if offset != 0 {
+		t = t.In(time.FixedZone("", int(offset)))
+	}
+	field.Set(reflect.ValueOf(t))
+	return nil
+}
@@ -0,0 +1,73 @@
+package binary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeRoundTrip(t *testing.T) {
+	type Event struct {
+		Name     string
+		At       time.Time
+		History  []time.Time
+		Deadline *time.Time
+		Zero     time.Time
+	}
+
+	tokyo := time.FixedZone("JST", 9*3600)
+	deadline := time.Date(2262, 4, 11, 23, 47, 16, 854775807, time.UTC).Add(time.Hour)
+	original := Event{
+		Name: "launch",
+		// time.Now carries a monotonic clock reading which is not encoded
+		At: time.Now(),
+		History: []time.Time{
+			time.Date(1969, 12, 31, 23, 59, 59, 1, time.UTC),
+			time.Date(2024, 2, 29, 12, 0, 0, 123456789, tokyo),
+			time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Deadline: &deadline,
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Event
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.True(t, original.At.Equal(decoded.At), "%v != %v", original.At, decoded.At)
+	assert.Len(t, decoded.History, len(original.History))
+	for i := range original.History {
+		assert.True(t, original.History[i].Equal(decoded.History[i]))
+	}
+	assert.True(t, deadline.Equal(*decoded.Deadline))
+	assert.True(t, decoded.Zero.IsZero())
+
+	// The zone offset is preserved
+	_, offset := decoded.History[1].Zone()
+	assert.Equal(t, 9*3600, offset)
+	assert.Equal(t, time.UTC, decoded.History[0].Location())
+}
+
+func TestTimeWireFormat(t *testing.T) {
+	ts := time.Unix(1, 2).In(time.FixedZone("", -3600))
+
+	data, err := Marshal(ts)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		1, 0, 0, 0, 0, 0, 0, 0, // Unix seconds
+		2, 0, 0, 0, // nanoseconds
+		0xF0, 0xF1, 0xFF, 0xFF, // offset of -3600 seconds
+	}, data)
+
+	var decoded time.Time
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.True(t, ts.Equal(decoded))
+	assert.Equal(t, ts.Format(time.RFC3339Nano), decoded.Format(time.RFC3339Nano))
+}
+
+func TestTimeInvalidNanoseconds(t *testing.T) {
+	data := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0x00, 0xCA, 0x9A, 0x3B, 0, 0, 0, 0}
+	var decoded time.Time
+	assert.Error(t, Unmarshal(data, &decoded))
+}
@@ -0,0 +1,53 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalIntoBareInterface(t *testing.T) {
+	// Circle and *Rect are registered in registry_test.go
+	var shape interface{} = &Rect{Width: 2, Height: 5, Label: "r"}
+
+	// A pointer to the interface marshals it with its presence byte and type code
+	data, err := Marshal(&shape)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 101, 0, 0, 0}, data[:5])
+
+	var decoded interface{}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, shape, decoded)
+	assert.IsType(t, &Rect{}, decoded)
+
+	// A destination already holding a value of another type is replaced
+	shape = Circle{Radius: 3}
+	data, err = Marshal(&shape)
+	assert.NoError(t, err)
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, Circle{Radius: 3}, decoded)
+}
+
+func TestUnmarshalIntoTopLevelInterface(t *testing.T) {
+	var shape Shape = Circle{Radius: 2}
+	data, err := Marshal(&shape)
+	assert.NoError(t, err)
+
+	var decoded Shape
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, 12.0, decoded.Area())
+
+	// Registered types that do not implement the destination interface are rejected
+	var custom interface{} = CustomType{Value: "x"}
+	data, err = Marshal(&custom)
+	assert.NoError(t, err)
+	assert.Error(t, Unmarshal(data, &decoded))
+
+	var nilShape Shape
+	data, err = Marshal(&nilShape)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0}, data)
+	decoded = Circle{Radius: 1}
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Nil(t, decoded)
+}
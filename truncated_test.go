@@ -0,0 +1,74 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncatedPayload(t *testing.T) {
+	type Message struct {
+		ID    uint32
+		Name  string
+		Tags  []string
+		Score uint64 `binary:"varint"`
+		Data  [4]byte
+	}
+
+	data, err := Marshal(Message{ID: 1, Name: "name", Tags: []string{"a", "b"}, Score: 300, Data: [4]byte{1, 2, 3, 4}})
+	assert.NoError(t, err)
+
+	// One byte short of complete is truncation, not corruption
+	var decoded Message
+	err = Unmarshal(data[:len(data)-1], &decoded)
+	assert.ErrorIs(t, err, ErrTruncated)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "Data", fieldErr.Path)
+
+	// Every shorter prefix is truncated as well, including an empty input
+	for cut := 0; cut < len(data); cut++ {
+		assert.ErrorIs(t, Unmarshal(data[:cut], &decoded), ErrTruncated, "cut at %d", cut)
+	}
+
+	// Waiting for the rest of the data succeeds
+	assert.NoError(t, Unmarshal(data, &decoded))
+}
+
+func TestTruncatedStream(t *testing.T) {
+	data, err := Marshal(struct {
+		A uint16
+		B string
+	}{A: 1, B: "hello"})
+	assert.NoError(t, err)
+
+	var decoded struct {
+		A uint16
+		B string
+	}
+	err = NewDecoder(bytes.NewReader(data[:len(data)-1])).Decode(&decoded)
+	assert.ErrorIs(t, err, ErrTruncated)
+
+	// The end of the stream between values is still io.EOF
+	assert.Equal(t, io.EOF, NewDecoder(bytes.NewReader(nil)).Decode(&decoded))
+}
+
+func TestCorruptPayloadIsNotTruncated(t *testing.T) {
+	type Message struct {
+		Value *uint32
+	}
+
+	var decoded Message
+	err := Unmarshal([]byte{7, 0, 0, 0, 0}, &decoded)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrTruncated))
+
+	// Trailing data means the input is too long, not too short
+	err = Unmarshal([]byte{0, 1}, &decoded)
+	assert.ErrorIs(t, err, ErrTrailingData)
+	assert.False(t, errors.Is(err, ErrTruncated))
+}
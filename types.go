@@ -3,8 +3,25 @@
 //
 // Main APIs:
 //   - Marshal(v interface{}) ([]byte, error): Serialize any Go value to binary data
+//   - MarshalTo(w io.Writer, v interface{}) (int64, error): Serialize a value to a writer and report the bytes written
 //   - Unmarshal(data []byte, v interface{}) error: Deserialize binary data to Go value
+//   - Codec: Marshal and Unmarshal with options such as the byte order and the length prefix width
+//   - MarshalT[T any](v T) ([]byte, error) and UnmarshalT[T any](data []byte) (T, error): Type-safe generic wrappers
+//   - MustMarshal and MustUnmarshal: Like Marshal and Unmarshal, but panic on error
+//   - SafeUnmarshal: Like Unmarshal, but returns panics in user methods as errors
+//   - MarshalContext and UnmarshalContext: Like Marshal and Unmarshal, but stop once a context is canceled
+//   - MarshalHex, UnmarshalHex, MarshalBase64 and UnmarshalBase64: Use hex or base64 text instead of raw bytes
+//   - MarshalValue and UnmarshalValue: Operate on a reflect.Value instead of an interface{}
+//   - Equal(a, b interface{}) (bool, error): Compare the encodings of two values
+//   - MarshalWithChecksum and UnmarshalWithChecksum: Append and verify a CRC32 trailer
+//   - MarshalGroup and UnmarshalGroup: Process only the struct fields of a group
+//   - MarshalVersioned and UnmarshalVersioned: Prepend and strip a 2-byte version for schema evolution
+//   - MarshalTyped and UnmarshalTyped: Prepend and verify a hash of the type layout
+//   - NewEncoder(w io.Writer) *Encoder: Write a stream of values to a writer
+//   - NewDecoder(r io.Reader) *Decoder: Read a stream of values from a reader
+//   - UnmarshalReader(r io.Reader, v interface{}) error: Read a single value from a reader
 //   - UnmarshalPartial(data []byte, v interface{}) (remaining int, error): Partial deserialization with remaining byte count
+//   - UnmarshalLenient(data []byte, v interface{}) error: Deserialize a value and ignore any trailing bytes
 //
 // The UnmarshalPartial function allows for partial parsing of data streams,
 // returning the number of bytes that remain unprocessed. This is useful for:
@@ -14,20 +31,42 @@
 //
 // Supported data types:
 //   - Integer types: uint8, uint16, uint32, uint64, int8, int16, int32, int64
+//   - Platform-sized integers: int, uint, uintptr (always encoded as 8 bytes)
 //   - Boolean type: bool
 //   - Floating point types: float32, float64
+//   - Complex types: complex64, complex128 (real part followed by imaginary part)
 //   - String
 //   - Byte slice ([]byte)
 //   - Byte arrays ([N]byte)
 //   - Other slices
 //   - Other arrays
+//   - Maps
+//   - time.Time
+//   - net.IP and net.IPNet
+//   - math/big.Int and math/big.Rat
+//   - Pointers, encoded with a presence byte so nil pointers round-trip
+//   - Raw, pre-encoded bytes embedded without a length prefix
+//   - Interfaces holding types registered with RegisterType, or nil
 //   - Structs
 //   - Nested structs
 //
 // Custom types can implement BinaryMarshaler and BinaryUnmarshaler interfaces
-// for custom serialization behavior.
+// for custom serialization behavior. Types implementing BinaryUnmarshalerN also
+// report how many bytes they consumed to UnmarshalPartial.
+//
+// Structs can implement PreMarshaler and PostUnmarshaler to run hooks before
+// encoding and after decoding. Types implementing ForwardCompatible accept
+// trailing fields written by newer versions of the type.
+//
+// The package functions are safe for concurrent use by multiple goroutines:
+// each call keeps its state to itself, and the shared state, the type registry
+// used by RegisterType and internal caches such as the pool of Marshal buffers,
+// is synchronized. An Encoder or Decoder must not be used by several goroutines
+// at the same time.
 package binary
 
+import "reflect"
+
 // BinaryMarshaler is the interface implemented by types that can marshal themselves into binary form.
 type BinaryMarshaler interface {
 	MarshalBinary() ([]byte, error)
@@ -37,3 +76,105 @@ type BinaryMarshaler interface {
 type BinaryUnmarshaler interface {
 	UnmarshalBinary([]byte) error
 }
+
+// BinaryUnmarshalerN is an optional interface for types that unmarshal themselves
+// from the start of data and report how many bytes they consumed. UnmarshalPartial
+// uses it to return the true remainder, so several custom-encoded values can be
+// decoded back-to-back from one buffer.
+type BinaryUnmarshalerN interface {
+	UnmarshalBinaryN(data []byte) (consumed int, err error)
+}
+
+// ForwardCompatible is a marker interface for types whose encoding may be
+// followed by fields added in newer versions of the type. When Unmarshal
+// decodes into a value implementing it, the bytes that remain after all known
+// fields are ignored instead of causing an ErrTrailingData error, so an older
+// program can read data written by a newer one. This only works for fields
+// appended at the end of the top-level value: new fields of nested structs or
+// of slice elements shift everything after them and cannot be skipped.
+type ForwardCompatible interface {
+	ForwardCompatible()
+}
+
+// PreMarshaler is the interface implemented by structs that need to prepare
+// themselves before being encoded, e.g. to normalize their data. PreMarshal
+// is called before the fields of the struct are encoded.
+type PreMarshaler interface {
+	PreMarshal() error
+}
+
+// PostUnmarshaler is the interface implemented by structs that need to validate
+// or complete themselves after being decoded. PostUnmarshal is called once all
+// fields of the struct have been decoded.
+type PostUnmarshaler interface {
+	PostUnmarshal() error
+}
+
+// asMarshaler returns v as a BinaryMarshaler if it implements one.
+// Types with a built-in encoding, such as time.Time, and pointers to them are
+// never treated as custom marshalers.
+func asMarshaler(v interface{}) (BinaryMarshaler, bool) {
+	if t := reflect.TypeOf(v); t != nil && (isBuiltinType(t) || t.Kind() == reflect.Ptr && isBuiltinType(t.Elem())) {
+		return nil, false
+	}
+	marshaler, ok := v.(BinaryMarshaler)
+	return marshaler, ok
+}
+
+// fieldMarshaler returns the BinaryMarshaler of a struct field. A value field
+// whose type implements MarshalBinary on a pointer receiver is marshaled through
+// its address, or through a pointer to a copy if the field is not addressable.
+func fieldMarshaler(field reflect.Value) (BinaryMarshaler, bool) {
+	if marshaler, ok := asMarshaler(field.Interface()); ok {
+		return marshaler, true
+	}
+	if field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		return nil, false
+	}
+	// Check the method set first, so that fields without methods are not copied
+	if !reflect.PointerTo(field.Type()).Implements(marshalerType) {
+		return nil, false
+	}
+	if field.CanAddr() {
+		return asMarshaler(field.Addr().Interface())
+	}
+	ptr := reflect.New(field.Type())
+	ptr.Elem().Set(field)
+	return asMarshaler(ptr.Interface())
+}
+
+// fieldUnmarshaler returns the BinaryUnmarshaler of an addressable struct field.
+// A value field is decoded in place through its address, which is only possible
+// when its type implements UnmarshalBinary on a pointer receiver.
+func fieldUnmarshaler(field reflect.Value) (BinaryUnmarshaler, bool) {
+	if field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface || !field.CanAddr() {
+		return nil, false
+	}
+	// Check the method set first, so that fields without methods are not copied
+	if !reflect.PointerTo(field.Type()).Implements(unmarshalerType) {
+		return nil, false
+	}
+	return asUnmarshaler(field.Addr().Interface())
+}
+
+// asUnmarshaler returns v as a BinaryUnmarshaler if it implements one.
+// Pointers to types with a built-in encoding are never treated as custom unmarshalers.
+func asUnmarshaler(v interface{}) (BinaryUnmarshaler, bool) {
+	if t := reflect.TypeOf(v); t != nil && t.Kind() == reflect.Ptr && isBuiltinType(t.Elem()) {
+		return nil, false
+	}
+	unmarshaler, ok := v.(BinaryUnmarshaler)
+	return unmarshaler, ok
+}
+
+// isBuiltinType reports whether t has a built-in encoding that takes precedence over its own methods
+func isBuiltinType(t reflect.Type) bool {
+	return t == timeType || t == ipType || t == ipNetType || t == bigIntType || t == bigRatType
+}
+
+// isEmbeddedStruct reports whether a struct field is an embedded struct whose
+// fields are encoded inline. Embedded pointers and built-in types such as
+// time.Time are encoded like regular fields.
+func isEmbeddedStruct(field reflect.StructField) bool {
+	return field.Anonymous && field.Type.Kind() == reflect.Struct && !isBuiltinType(field.Type)
+}
@@ -0,0 +1,29 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalLenient(t *testing.T) {
+	type Header struct {
+		Version uint16
+		Name    string
+	}
+
+	data, err := Marshal(Header{Version: 2, Name: "v2"})
+	assert.NoError(t, err)
+	data = append(data, 0xde, 0xad)
+
+	// Unmarshal rejects the trailing bytes
+	var strict Header
+	assert.Error(t, Unmarshal(data, &strict))
+
+	var lenient Header
+	assert.NoError(t, UnmarshalLenient(data, &lenient))
+	assert.Equal(t, Header{Version: 2, Name: "v2"}, lenient)
+
+	// Errors while decoding the value are still reported
+	assert.Error(t, UnmarshalLenient(data[:3], &lenient))
+}
@@ -0,0 +1,35 @@
+package binary
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalReader(t *testing.T) {
+	type Header struct {
+		Kind uint8
+		Name string
+	}
+
+	header, err := Marshal(Header{Kind: 2, Name: "log"})
+	assert.NoError(t, err)
+	r := strings.NewReader(string(header) + "trailer")
+
+	var decoded Header
+	assert.NoError(t, UnmarshalReader(r, &decoded))
+	assert.Equal(t, Header{Kind: 2, Name: "log"}, decoded)
+
+	// The reader is left right after the value
+	rest, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "trailer", string(rest))
+}
+
+func TestUnmarshalReaderEOF(t *testing.T) {
+	var v uint32
+	assert.Equal(t, io.EOF, UnmarshalReader(strings.NewReader(""), &v))
+	assert.ErrorIs(t, UnmarshalReader(strings.NewReader("\x01\x02"), &v), io.ErrUnexpectedEOF)
+}
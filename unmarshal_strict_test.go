@@ -0,0 +1,51 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalStrict(t *testing.T) {
+	type Message struct {
+		ID   uint32
+		Name string
+	}
+
+	data, err := Marshal(Message{ID: 1, Name: "abc"})
+	assert.NoError(t, err)
+
+	var decoded Message
+	consumed, err := UnmarshalStrict(data, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), consumed)
+	assert.Equal(t, Message{ID: 1, Name: "abc"}, decoded)
+
+	// Trailing data is an error, reporting where the value ended
+	withTrailer := append(append([]byte{}, data...), 0xEE, 0xFF)
+	consumed, err = UnmarshalStrict(withTrailer, &decoded)
+	assert.Error(t, err)
+	var trailing *TrailingDataError
+	if assert.ErrorAs(t, err, &trailing) {
+		assert.Equal(t, 2, trailing.Remaining)
+	}
+	assert.Equal(t, len(data), consumed)
+	assert.Equal(t, byte(0xEE), withTrailer[consumed])
+}
+
+func TestUnmarshalStrictDecodeError(t *testing.T) {
+	type Message struct {
+		ID   uint32
+		Name string
+	}
+
+	// The ID is read before the string length prefix runs out of data
+	var decoded Message
+	consumed, err := UnmarshalStrict([]byte{1, 0, 0, 0, 5, 0}, &decoded)
+	assert.Error(t, err)
+	assert.Equal(t, 6, consumed)
+
+	consumed, err = UnmarshalStrict([]byte{1, 2}, decoded)
+	assert.Error(t, err)
+	assert.Equal(t, 0, consumed)
+}
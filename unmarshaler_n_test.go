@@ -0,0 +1,71 @@
+package binary
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tlv is a custom-encoded value of one length byte followed by that many bytes
+type tlv struct {
+	Value string
+}
+
+func (v tlv) MarshalBinary() ([]byte, error) {
+	return append([]byte{byte(len(v.Value))}, v.Value...), nil
+}
+
+func (v *tlv) UnmarshalBinary(data []byte) error {
+	_, err := v.UnmarshalBinaryN(data)
+	return err
+}
+
+func (v *tlv) UnmarshalBinaryN(data []byte) (int, error) {
+	if len(data) == 0 || len(data) < 1+int(data[0]) {
+		return 0, fmt.Errorf("short tlv")
+	}
+	n := int(data[0])
+	v.Value = string(data[1 : 1+n])
+	return 1 + n, nil
+}
+
+// badTLV reports more bytes than it was given
+type badTLV struct{}
+
+func (*badTLV) UnmarshalBinaryN(data []byte) (int, error) {
+	return len(data) + 1, nil
+}
+
+func TestUnmarshalPartialBinaryUnmarshalerN(t *testing.T) {
+	first, err := Marshal(tlv{Value: "hello"})
+	assert.NoError(t, err)
+	second, err := Marshal(tlv{Value: "go"})
+	assert.NoError(t, err)
+	data := append(first, second...)
+
+	var a, b tlv
+	remaining, err := UnmarshalPartial(data, &a)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", a.Value)
+	assert.Equal(t, len(second), remaining)
+
+	remaining, err = UnmarshalPartial(data[len(data)-remaining:], &b)
+	assert.NoError(t, err)
+	assert.Equal(t, "go", b.Value)
+	assert.Equal(t, 0, remaining)
+
+	// Unmarshal reports the bytes the value did not consume
+	assert.Error(t, Unmarshal(data, &a))
+	assert.NoError(t, Unmarshal(first, &a))
+}
+
+func TestUnmarshalPartialBinaryUnmarshalerNErrors(t *testing.T) {
+	var v tlv
+	remaining, err := UnmarshalPartial([]byte{5, 'a'}, &v)
+	assert.Error(t, err)
+	assert.Equal(t, 2, remaining)
+
+	_, err = UnmarshalPartial([]byte{1, 2}, &badTLV{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package binary
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"unicode/utf16"
+)
+
+// utf16Tag makes a string field be written as UTF-16 code units, e.g. for
+// interoperating with Windows APIs. The length prefix counts code units, not
+// bytes, and each code unit is written in the codec's byte order, so strings
+// are UTF-16LE by default. Characters beyond the Basic Multilingual Plane take
+// two code units, a surrogate pair.
+const utf16Tag = "utf16"
+
+// encodeUTF16String writes a string as its length in UTF-16 code units followed
+// by the code units. Invalid UTF-8 is written as U+FFFD.
+func encodeUTF16String(s string, buf *encodeState, tag string) error {
+	units := utf16.Encode([]rune(s))
+	if err := writeLength(buf, len(units), tag); err != nil {
+		return err
+	}
+	data := make([]byte, 2*len(units))
+	for i, unit := range units {
+		buf.byteOrder().PutUint16(data[2*i:], unit)
+	}
+	_, err := buf.Write(data)
+	return err
+}
+
+// decodeUTF16String reads a string written by encodeUTF16String. Unpaired
+// surrogates are decoded as U+FFFD.
+func decodeUTF16String(buf *decodeState, field reflect.Value, tag string) error {
+	length, err := readLength(buf, tag)
+	if err != nil {
+		return err
+	}
+	if length > math.MaxInt/2 {
+		return fmt.Errorf("length %d exceeds maximum supported length", length)
+	}
+	if err := buf.checkLength(2 * length); err != nil {
+		return err
+	}
+	data := make([]byte, 2*length)
+	if err := buf.readFull(data); err != nil {
+		return err
+	}
+	units := make([]uint16, length)
+	for i := range units {
+		units[i] = buf.byteOrder().Uint16(data[2*i:])
+	}
+	field.SetString(string(utf16.Decode(units)))
+	return nil
+}
@@ -0,0 +1,81 @@
+package binary
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUTF16String(t *testing.T) {
+	type Record struct {
+		Name  string `binary:"utf16"`
+		Emoji string `binary:"utf16,prefix:2"`
+		Plain string
+	}
+
+	original := Record{Name: "Größe", Emoji: "a😀", Plain: "ok"}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		5, 0, 0, 0, 'G', 0, 'r', 0, 0xf6, 0, 0xdf, 0, 'e', 0, // BMP characters take one code unit
+		3, 0, 'a', 0, 0x3d, 0xd8, 0x00, 0xde, // U+1F600 is the surrogate pair D83D DE00
+		2, 0, 0, 0, 'o', 'k',
+	}, data)
+
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestUTF16StringByteOrder(t *testing.T) {
+	type Record struct {
+		Name string `binary:"utf16"`
+	}
+	codec := NewCodec().WithByteOrder(binary.BigEndian)
+
+	data, err := codec.Marshal(Record{Name: "hi😀"})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 4, 0, 'h', 0, 'i', 0xd8, 0x3d, 0xde, 0x00}, data)
+
+	var decoded Record
+	assert.NoError(t, codec.Unmarshal(data, &decoded))
+	assert.Equal(t, "hi😀", decoded.Name)
+}
+
+func TestUTF16StringElements(t *testing.T) {
+	type Record struct {
+		Names  []string          `binary:"utf16"`
+		Labels map[string]string `binary:"utf16,countvarint"`
+	}
+
+	original := Record{
+		Names:  []string{"", "Ω", "🎉🎉"},
+		Labels: map[string]string{"κ": "値"},
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Record
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestUTF16StringMalformed(t *testing.T) {
+	type Record struct {
+		Name string `binary:"utf16"`
+	}
+
+	// An unpaired surrogate decodes as the replacement character
+	var decoded Record
+	assert.NoError(t, Unmarshal([]byte{2, 0, 0, 0, 0x3d, 0xd8, 'x', 0}, &decoded))
+	assert.Equal(t, "�x", decoded.Name)
+
+	// The prefix counts code units, so an odd number of bytes is truncated data
+	assert.Error(t, Unmarshal([]byte{2, 0, 0, 0, 'x', 0, 'y'}, &decoded))
+
+	_, err := Marshal(struct {
+		Name string `binary:"utf16,8"`
+	}{})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
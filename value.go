@@ -0,0 +1,36 @@
+package binary
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MarshalValue serializes the value held by rv, for callers such as code
+// generators and plugins that only have a reflect.Value at hand. The output is
+// the same as Marshal(rv.Interface()). rv must be valid and must not have been
+// obtained through unexported struct fields.
+func MarshalValue(rv reflect.Value) ([]byte, error) {
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("cannot marshal an invalid reflect.Value")
+	}
+	if !rv.CanInterface() {
+		return nil, fmt.Errorf("cannot marshal a %s obtained through an unexported field", rv.Type())
+	}
+	return Marshal(rv.Interface())
+}
+
+// UnmarshalValue deserializes binary data into rv and returns the number of
+// remaining bytes, like UnmarshalPartial. rv must either be settable, such as
+// reflect.New(t).Elem() or a field of an addressable struct, or be a non-nil pointer.
+func UnmarshalValue(data []byte, rv reflect.Value) (remaining int, err error) {
+	switch {
+	case !rv.IsValid():
+		return len(data), fmt.Errorf("cannot unmarshal into an invalid reflect.Value")
+	case rv.CanSet():
+		return UnmarshalPartial(data, rv.Addr().Interface())
+	case rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.CanInterface():
+		return UnmarshalPartial(data, rv.Interface())
+	default:
+		return len(data), fmt.Errorf("cannot unmarshal into a %s that is neither settable nor a non-nil pointer", rv.Type())
+	}
+}
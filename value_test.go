@@ -0,0 +1,86 @@
+package binary
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalValueDynamicStruct(t *testing.T) {
+	// A struct type built at run time, as a code generator or plugin might do
+	typ := reflect.StructOf([]reflect.StructField{
+		{Name: "ID", Type: reflect.TypeOf(uint32(0))},
+		{Name: "Name", Type: reflect.TypeOf(""), Tag: `binary:"prefix:1"`},
+		{Name: "Scores", Type: reflect.TypeOf([]uint16(nil))},
+	})
+
+	original := reflect.New(typ).Elem()
+	original.Field(0).SetUint(42)
+	original.Field(1).SetString("dyn")
+	original.Field(2).Set(reflect.ValueOf([]uint16{1, 2}))
+
+	data, err := MarshalValue(original)
+	assert.NoError(t, err)
+	expected, err := Marshal(original.Interface())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, data)
+	assert.Equal(t, []byte{42, 0, 0, 0, 3, 'd', 'y', 'n'}, data[:8])
+
+	decoded := reflect.New(typ).Elem()
+	remaining, err := UnmarshalValue(data, decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, original.Interface(), decoded.Interface())
+}
+
+func TestUnmarshalValueTargets(t *testing.T) {
+	data, err := MarshalValue(reflect.ValueOf(uint16(0x0102)))
+	assert.NoError(t, err)
+	data = append(data, 0xFF)
+
+	// A non-nil pointer
+	ptr := reflect.New(reflect.TypeOf(uint16(0)))
+	remaining, err := UnmarshalValue(data, ptr)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, uint16(0x0102), ptr.Elem().Interface())
+
+	// A settable field of a struct
+	var holder struct{ Value uint16 }
+	field := reflect.ValueOf(&holder).Elem().Field(0)
+	_, err = UnmarshalValue(data, field)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x0102), holder.Value)
+
+	// A settable nil pointer is allocated
+	var target *uint16
+	_, err = UnmarshalValue(data, reflect.ValueOf(&target).Elem())
+	assert.NoError(t, err)
+	if assert.NotNil(t, target) {
+		assert.Equal(t, uint16(0x0102), *target)
+	}
+}
+
+func TestValueErrors(t *testing.T) {
+	_, err := MarshalValue(reflect.Value{})
+	assert.Error(t, err)
+
+	hidden := struct{ secret uint8 }{secret: 1}
+	_, err = MarshalValue(reflect.ValueOf(hidden).Field(0))
+	assert.Error(t, err)
+
+	_, err = UnmarshalValue([]byte{1}, reflect.Value{})
+	assert.Error(t, err)
+
+	// Values that are neither settable nor pointers cannot be filled in
+	remaining, err := UnmarshalValue([]byte{1}, reflect.ValueOf(uint8(0)))
+	assert.Error(t, err)
+	assert.Equal(t, 1, remaining)
+
+	_, err = UnmarshalValue([]byte{1}, reflect.ValueOf((*uint8)(nil)))
+	assert.Error(t, err)
+
+	_, err = UnmarshalValue([]byte{1}, reflect.ValueOf(&hidden).Elem().Field(0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package binary
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// isIntegerKind reports whether k is a signed or unsigned integer kind
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// encodeVarint serializes an integer as a variable-length integer.
+// Unsigned integers use the uvarint encoding and signed integers the zigzag varint encoding.
+func encodeVarint(field reflect.Value, buf *encodeState) error {
+	var data [binary.MaxVarintLen64]byte
+	var n int
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = binary.PutVarint(data[:], field.Int())
+	default:
+		n = binary.PutUvarint(data[:], field.Uint())
+	}
+	_, err := buf.Write(data[:n])
+	return err
+}
+
+// decodeVarint deserializes a variable-length integer written by encodeVarint
+func decodeVarint(buf *decodeState, field reflect.Value) error {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := binary.ReadVarint(buf)
+		if err != nil {
+			return err
+		}
+		if field.OverflowInt(v) {
+			return fmt.Errorf("varint value %d overflows %s", v, field.Type())
+		}
+		field.SetInt(v)
+	default:
+		v, err := binary.ReadUvarint(buf)
+		if err != nil {
+			return err
+		}
+		if field.OverflowUint(v) {
+			return fmt.Errorf("varint value %d overflows %s", v, field.Type())
+		}
+		field.SetUint(v)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package binary
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVarintBoundaryValues(t *testing.T) {
+	type Unsigned struct {
+		V uint64 `binary:"varint"`
+	}
+	type Signed struct {
+		V int64 `binary:"varint"`
+	}
+
+	unsigned := []struct {
+		value uint64
+		size  int
+	}{
+		{0, 1}, {127, 1}, {128, 2}, {16383, 2}, {16384, 3}, {math.MaxUint64, 10},
+	}
+	for _, test := range unsigned {
+		data, err := Marshal(Unsigned{test.value})
+		assert.NoError(t, err)
+		assert.Len(t, data, test.size, "value %d", test.value)
+
+		var decoded Unsigned
+		assert.NoError(t, Unmarshal(data, &decoded))
+		assert.Equal(t, test.value, decoded.V)
+	}
+
+	signed := []struct {
+		value int64
+		size  int
+	}{
+		{0, 1}, {-1, 1}, {63, 1}, {-64, 1}, {64, 2}, {127, 2}, {128, 2},
+		{math.MaxInt64, 10}, {math.MinInt64, 10},
+	}
+	for _, test := range signed {
+		data, err := Marshal(Signed{test.value})
+		assert.NoError(t, err)
+		assert.Len(t, data, test.size, "value %d", test.value)
+
+		var decoded Signed
+		assert.NoError(t, Unmarshal(data, &decoded))
+		assert.Equal(t, test.value, decoded.V)
+	}
+}
+
+func TestVarintFieldsAndSlices(t *testing.T) {
+	type Entry struct {
+		Seq    uint32           `binary:"varint"`
+		Delta  int16            `binary:"varint"`
+		Size   int              `binary:"varint"`
+		Offset *uint64          `binary:"varint"`
+		Counts []uint32         `binary:"varint"`
+		Fixed  [3]int64         `binary:"varint"`
+		Index  map[uint16]int32 `binary:"varint"`
+		Name   string           `binary:"varint"`
+		Plain  uint32
+	}
+
+	offset := uint64(300)
+	original := Entry{
+		Seq:    1,
+		Delta:  -2,
+		Size:   1 << 40,
+		Offset: &offset,
+		Counts: []uint32{0, 127, 128},
+		Fixed:  [3]int64{-1, 0, 1},
+		Index:  map[uint16]int32{1: -1},
+		Name:   "n",
+		Plain:  5,
+	}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	// Seq(1) Delta(1) Size(6) Offset(1+2) Counts(4+1+1+2) Fixed(3) Index(4+1+1) Name(4+1) Plain(4)
+	assert.Len(t, data, 37)
+
+	var decoded Entry
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestVarintOverflow(t *testing.T) {
+	type Wide struct {
+		V uint64 `binary:"varint"`
+	}
+	type Narrow struct {
+		V uint8 `binary:"varint"`
+	}
+
+	data, err := Marshal(Wide{256})
+	assert.NoError(t, err)
+
+	var decoded Narrow
+	assert.Error(t, Unmarshal(data, &decoded))
+
+	// Truncated varint
+	assert.Error(t, Unmarshal([]byte{0x80}, &decoded))
+}
+
+func TestVarintSignedSlices(t *testing.T) {
+	type Samples struct {
+		Small []int32 `binary:"varint"`
+		Wide  []int64 `binary:"varint,countvarint"`
+	}
+	type FixedSamples struct {
+		Small []int32
+		Wide  []int64
+	}
+
+	values := []int32{-1, 0, 1, -1000000}
+	original := Samples{Small: values, Wide: []int64{-1, 0, 1, -1000000, math.MinInt64}}
+
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		4, 0, 0, 0, // uint32 element count
+		0x01,             // -1 zigzags to 1
+		0x00,             // 0
+		0x02,             // 1 zigzags to 2
+		0xFF, 0x88, 0x7A, // -1000000 zigzags to 1999999
+	}, data[:10])
+	// With countvarint the count is a varint as well
+	assert.Equal(t, byte(5), data[10])
+
+	var decoded Samples
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, original, decoded)
+
+	fixed, err := Marshal(FixedSamples{Small: original.Small, Wide: original.Wide})
+	assert.NoError(t, err)
+	assert.Less(t, len(data), len(fixed))
+	assert.Equal(t, 4+4*4+4+5*8, len(fixed))
+}
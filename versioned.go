@@ -0,0 +1,27 @@
+package binary
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MarshalVersioned marshals v and prepends a 2-byte little-endian version, so that
+// readers can tell which struct layout the payload was written with
+func MarshalVersioned(version uint16, v interface{}) ([]byte, error) {
+	data := binary.LittleEndian.AppendUint16(nil, version)
+	data, err := MarshalAppend(data, v)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// UnmarshalVersioned strips the version written by MarshalVersioned and returns it
+// together with the still-encoded payload, leaving it to the caller to decode the
+// payload into the struct that matches the version. The payload shares memory with data.
+func UnmarshalVersioned(data []byte) (version uint16, payload []byte, err error) {
+	if len(data) < 2 {
+		return 0, nil, fmt.Errorf("versioned data of %d bytes has no version header: %w", len(data), errUnexpectedEOF)
+	}
+	return binary.LittleEndian.Uint16(data), data[2:], nil
+}
@@ -0,0 +1,62 @@
+package binary
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type settingsV1 struct {
+	Name string
+}
+
+type settingsV2 struct {
+	Name    string
+	Retries uint8
+}
+
+// decodeSettings dispatches on the version and upgrades old payloads to the current layout
+func decodeSettings(t *testing.T, data []byte) settingsV2 {
+	version, payload, err := UnmarshalVersioned(data)
+	assert.NoError(t, err)
+	switch version {
+	case 1:
+		var v1 settingsV1
+		assert.NoError(t, Unmarshal(payload, &v1))
+		return settingsV2{Name: v1.Name, Retries: 3}
+	case 2:
+		var v2 settingsV2
+		assert.NoError(t, Unmarshal(payload, &v2))
+		return v2
+	default:
+		t.Fatalf("unexpected version %d", version)
+		return settingsV2{}
+	}
+}
+
+func TestVersionedRoundTrip(t *testing.T) {
+	v1, err := MarshalVersioned(1, settingsV1{Name: "old"})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 0, 3, 0, 0, 0, 'o', 'l', 'd'}, v1)
+
+	v2, err := MarshalVersioned(2, settingsV2{Name: "new", Retries: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2, 0}, v2[:2])
+
+	assert.Equal(t, settingsV2{Name: "old", Retries: 3}, decodeSettings(t, v1))
+	assert.Equal(t, settingsV2{Name: "new", Retries: 5}, decodeSettings(t, v2))
+}
+
+func TestVersionedErrors(t *testing.T) {
+	_, err := MarshalVersioned(1, make(chan int))
+	assert.Error(t, err)
+
+	_, _, err = UnmarshalVersioned([]byte{1})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	version, payload, err := UnmarshalVersioned([]byte{7, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(7), version)
+	assert.Empty(t, payload)
+}
@@ -0,0 +1,77 @@
+package binary
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoderZeroFillOnEOF(t *testing.T) {
+	type Record struct {
+		ID      uint32
+		Name    string  `binary:"8"`
+		Payload [6]byte `binary:"6"`
+		Digest  []byte  `binary:"32"`
+	}
+
+	original := Record{
+		ID:      9,
+		Name:    "backup",
+		Payload: [6]byte{1, 2, 3, 4, 5, 6},
+		Digest:  bytes.Repeat([]byte{0xAB}, 32),
+	}
+	data, err := Marshal(original)
+	assert.NoError(t, err)
+
+	// Cut the record in the middle of the Digest field
+	truncated := data[:len(data)-20]
+
+	// Strict behavior is the default
+	var strict Record
+	err = NewDecoder(bytes.NewReader(truncated)).Decode(&strict)
+	assert.Error(t, err)
+
+	dec := NewDecoder(bytes.NewReader(truncated))
+	dec.ZeroFillOnEOF(true)
+	var decoded Record
+	err = dec.Decode(&decoded)
+	assert.NoError(t, err)
+	assert.True(t, dec.Truncated())
+	assert.Equal(t, original.ID, decoded.ID)
+	assert.Equal(t, original.Name, decoded.Name)
+	assert.Equal(t, original.Payload, decoded.Payload)
+	assert.Equal(t, append(bytes.Repeat([]byte{0xAB}, 12), make([]byte, 20)...), decoded.Digest)
+}
+
+func TestDecoderZeroFillOnEOFCompleteData(t *testing.T) {
+	type Record struct {
+		Name string `binary:"4"`
+	}
+
+	data, err := Marshal(Record{Name: "abcd"})
+	assert.NoError(t, err)
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.ZeroFillOnEOF(true)
+	var decoded Record
+	assert.NoError(t, dec.Decode(&decoded))
+	assert.False(t, dec.Truncated())
+	assert.Equal(t, "abcd", decoded.Name)
+}
+
+func TestDecoderZeroFillOnEOFOnlyFixedFields(t *testing.T) {
+	type Record struct {
+		ID   uint32
+		Name string
+	}
+
+	data, err := Marshal(Record{ID: 1, Name: "hello"})
+	assert.NoError(t, err)
+
+	// Length-prefixed fields are not fixed-length and still fail when truncated
+	dec := NewDecoder(bytes.NewReader(data[:6]))
+	dec.ZeroFillOnEOF(true)
+	var decoded Record
+	assert.Error(t, dec.Decode(&decoded))
+}